@@ -0,0 +1,248 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: batch.go
+
+Operacje na całym zestawie znaków (zakładka "Zestaw" panelu bocznego):
+- zakres: "Od" / "Do" jako indeksy znaków lub kody (kod = pierwszy kod + indeks);
+  puste pole = początek / koniec listy; kod można wpisać jako 65, 0x41 lub 'A
+- wszystkie przekształcenia z zakładki "Znak" (przesunięcie, obrót, lustra, negatyw)
+- wyśrodkowanie w poziomie, wyrównanie do linii bazowej, przycięcie
+  lewych pustych kolumn i margines z lewej
+
+Cała operacja to jeden krok cofania (Ctrl+Z).
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// domyślny kod pierwszego znaku listy (spacja)
+const DefaultFirstCode = 32
+
+// parseGlyphRef - numer znaku: dziesiętnie, 0x.. szesnastkowo lub 'A
+func parseGlyphRef(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "'") {
+		r, n := utf8.DecodeRuneInString(s[1:])
+		if n == 0 || r == utf8.RuneError {
+			return 0, fmt.Errorf("zły znak: %s", s)
+		}
+		return int(r), nil
+	}
+	v, err := strconv.ParseInt(s, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("zła liczba: %s", s)
+	}
+	return int(v), nil
+}
+
+// batchRange zwraca zakres indeksów [from, to] z pól "Od" / "Do"
+func (g *Game) batchRange() (from, to int, err error) {
+	from, to = 0, len(g.glyphs)-1
+	ofs := 0
+	if g.batchByCode {
+		ofs = g.firstCode
+	}
+	if s := strings.TrimSpace(g.batchFrom.text); s != "" {
+		v, err := parseGlyphRef(s)
+		if err != nil {
+			return 0, 0, err
+		}
+		from = v - ofs
+	}
+	if s := strings.TrimSpace(g.batchTo.text); s != "" {
+		v, err := parseGlyphRef(s)
+		if err != nil {
+			return 0, 0, err
+		}
+		to = v - ofs
+	}
+	from = max(from, 0)
+	to = min(to, len(g.glyphs)-1)
+	if from > to {
+		return 0, 0, fmt.Errorf("pusty zakres")
+	}
+	return from, to, nil
+}
+
+// applyBatch stosuje przekształcenie do znaków z zakresu jako jeden krok cofania
+func (g *Game) applyBatch(name string, fn func(Glyph) Glyph) {
+	if g.fontListLocked() {
+		return
+	}
+	if len(g.glyphs) == 0 {
+		g.lastExport = "Brak zapisanych znaków"
+		return
+	}
+	from, to, err := g.batchRange()
+	if err != nil {
+		g.lastExport = "Zakres: " + err.Error()
+		return
+	}
+
+	g.pushListUndo()
+	g.keepGrid() // niezapisane zmiany aktywnego znaku też idą do zestawu
+	glyphs := append([]Glyph(nil), g.glyphs...)
+	for i := from; i <= to; i++ {
+		glyphs[i] = fn(glyphs[i])
+	}
+	g.glyphs = glyphs
+
+	// aktywny znak z zakresu pokazujemy od razu na siatce
+	if g.activeGlyph >= from && g.activeGlyph <= to {
+		g.cells = g.glyphs[g.activeGlyph]
+	}
+	g.updateDisplayGlyphs()
+	g.updatePreviewText()
+	g.lastExport = fmt.Sprintf("%s: znaki %d..%d", name, from, to)
+}
+
+// glyphBounds zwraca skrajne zapalone kolumny i wiersze znaku (ok = false dla pustego)
+func glyphBounds(gl Glyph) (minX, minY, maxX, maxY int, ok bool) {
+	minX, minY, maxX, maxY = GridW, GridH, -1, -1
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			if gl[y][x] == 0 {
+				continue
+			}
+			minX, maxX = min(minX, x), max(maxX, x)
+			minY, maxY = min(minY, y), max(maxY, y)
+		}
+	}
+	return minX, minY, maxX, maxY, maxX >= 0
+}
+
+// centerGlyphH wyśrodkowuje znak w poziomie (przy nieparzystym luzie zostaje bliżej lewej)
+func centerGlyphH(gl Glyph) Glyph {
+	minX, _, maxX, _, ok := glyphBounds(gl)
+	if !ok {
+		return gl
+	}
+	dx := (GridW-(maxX-minX+1))/2 - minX
+	return shiftGlyph(gl, dx, 0, false)
+}
+
+// baselineGlyph przesuwa znak w pionie tak, aby najniższy zapalony wiersz
+// leżał na linii bazowej
+func baselineGlyph(gl Glyph, baseline int) Glyph {
+	_, _, _, maxY, ok := glyphBounds(gl)
+	if !ok {
+		return gl
+	}
+	return shiftGlyph(gl, 0, baseline-maxY, false)
+}
+
+// padGlyphLeft przesuwa znak tak, aby z lewej zostało pad pustych kolumn
+// (pad = 0 przycina puste kolumny)
+func padGlyphLeft(gl Glyph, pad int) Glyph {
+	minX, _, _, _, ok := glyphBounds(gl)
+	if !ok {
+		return gl
+	}
+	return shiftGlyph(gl, pad-minX, 0, false)
+}
+
+// batchButtons - przyciski zakładki "Zestaw"
+func (g *Game) batchButtons() []uiButton {
+	x0, y0, _, _ := sideRect()
+	const perRow = 4
+	bw := (OptionsW - 16) / perRow
+
+	rangeLabel := "Zakres: indeksy"
+	if g.batchByCode {
+		rangeLabel = fmt.Sprintf("Zakres: kody (od %d)", g.firstCode)
+	}
+	buttons := []uiButton{
+		{x0 + 8, y0 + 60, OptionsW - 16, 24, rangeLabel, func() { g.batchByCode = !g.batchByCode }},
+	}
+
+	ops := g.glyphOps()
+	rows := [][]int{{0, 1, 2, 3}, {4, 5, 6}, {7, 8, 9}}
+	yy := y0 + 92
+	for _, row := range rows {
+		for col, i := range row {
+			op := ops[i]
+			buttons = append(buttons, uiButton{
+				x0 + 8 + col*bw, yy, bw - 4, 24,
+				op.label, func() { g.applyBatch(op.label, op.apply) },
+			})
+		}
+		yy += 30
+	}
+
+	// wyrównanie
+	align := []struct {
+		label string
+		fn    func(Glyph) Glyph
+	}{
+		{"Środek", centerGlyphH},
+		{"Baza", func(gl Glyph) Glyph { return baselineGlyph(gl, g.baseline) }},
+		{"Przytnij", func(gl Glyph) Glyph { return padGlyphLeft(gl, 0) }},
+		{"Margines", func(gl Glyph) Glyph { return padGlyphLeft(gl, g.batchPad) }},
+	}
+	for col, a := range align {
+		buttons = append(buttons, uiButton{
+			x0 + 8 + col*bw, yy, bw - 4, 24,
+			a.label, func() { g.applyBatch(a.label, a.fn) },
+		})
+	}
+	return buttons
+}
+
+// handleBatchClick - kliknięcie w zakładce "Zestaw"
+func (g *Game) handleBatchClick(x, y, dir int) {
+	if dir < 0 {
+		return
+	}
+	x0, y0, _, _ := sideRect()
+	switch {
+	case y >= y0+28 && y < y0+52 && x >= x0+40 && x < x0+130:
+		g.focusInput(&g.batchFrom)
+	case y >= y0+28 && y < y0+52 && x >= x0+180 && x < x0+270:
+		g.focusInput(&g.batchTo)
+	default:
+		clickButtons(g.batchButtons(), x, y)
+	}
+}
+
+// drawBatchTab rysuje zakładkę "Zestaw"
+func (g *Game) drawBatchTab(screen *ebiten.Image) {
+	x0, y0, _, _ := sideRect()
+	drawText(screen, "Operacje na wszystkich znakach", x0+8, y0+20)
+
+	drawText(screen, "Od:", x0+8, y0+46)
+	g.batchFrom.draw(screen, x0+40, y0+28, 90, 24, g.focus == &g.batchFrom)
+	drawText(screen, "Do:", x0+148, y0+46)
+	g.batchTo.draw(screen, x0+180, y0+28, 90, 24, g.focus == &g.batchTo)
+
+	buttons := g.batchButtons()
+	for i, b := range buttons {
+		col := color.Color(btnColor)
+		if i == 0 && g.batchByCode {
+			col = btnColorAct
+		}
+		b.draw(screen, col)
+	}
+
+	last := buttons[len(buttons)-1]
+	yy := last.y + last.h + 20
+	desc := "cały zestaw"
+	if from, to, err := g.batchRange(); err != nil {
+		desc = err.Error()
+	} else if len(g.glyphs) > 0 {
+		desc = fmt.Sprintf("znaki %d..%d", from, to)
+	}
+	drawText(screen, "Zakres: "+desc, x0+8, yy)
+	drawText(screen, fmt.Sprintf("Linia bazowa: wiersz %d, margines: %d", g.baseline, g.batchPad), x0+8, yy+20)
+}
@@ -0,0 +1,243 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: browser.go
+
+Przeglądarka zapisanych znaków pod podglądem tekstu:
+- kilka rzędów miniatur z kodem i znakiem (kod = pierwszy kod + indeks)
+- kółko myszy przewija (g.glyphScroll w pikselach, wysokość g.glyphViewH)
+- kliknięcie wczytuje znak, przeciągnięcie zmienia kolejność,
+  ostatnie pole "+" to nowy znak
+- pole "Kod": wpisz 65, 0x41, 'A lub sam znak - przewija do znaku,
+  Enter wczytuje go do siatki
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// układ przeglądarki znaków
+const (
+	browserPad     = 6
+	browserHeaderH = 50 // pole "Kod" i opis znaku pod kursorem
+)
+
+// thumbScale - powiększenie pojedynczego piksela miniatury (duże znaki mniej)
+func thumbScale() int {
+	if max(GridW, GridH) > 8 {
+		return 2
+	}
+	return 3
+}
+
+// browserPitchX() / browserPitchY() - odstęp miniatur: miniatura + odstęp / podpis
+func browserPitchX() int { return max(GridW*thumbScale(), 24) + 10 }
+func browserPitchY() int { return GridH*thumbScale() + 16 }
+
+// browserRect - obszar przeglądarki (pod podglądem tekstu)
+func (g *Game) browserRect() (x, y, w, h int) {
+	x = EditorSize + 180 + 24
+	y = 12 + g.textViewH + 12
+	return x, y, CanvasW - OptionsW - x - 12, g.glyphViewH
+}
+
+func (g *Game) browserCols() int {
+	_, _, w, _ := g.browserRect()
+	return max(1, (w-2*browserPad)/browserPitchX())
+}
+
+// browserGridTop - górna krawędź siatki miniatur i jej widoczna wysokość
+func (g *Game) browserGridTop() (top, visibleH int) {
+	_, y, _, h := g.browserRect()
+	return y + browserHeaderH, h - browserHeaderH
+}
+
+func (g *Game) isInGlyphBrowser(x, y int) bool {
+	bx, by, bw, bh := g.browserRect()
+	return x >= bx && x < bx+bw && y >= by && y < by+bh
+}
+
+// thumbPos zwraca lewy górny róg miniatury znaku i (z uwzględnieniem przewinięcia)
+func (g *Game) thumbPos(i int) (x, y int) {
+	bx, _, _, _ := g.browserRect()
+	top, _ := g.browserGridTop()
+	cols := g.browserCols()
+	return bx + browserPad + (i%cols)*browserPitchX(), top + (i/cols)*browserPitchY() - g.glyphScroll
+}
+
+// thumbAt zwraca znak pod kursorem (len(g.glyphs) = pole nowego znaku, -1 = brak)
+// oraz slot - pozycję upuszczenia przy przeciąganiu
+func (g *Game) thumbAt(x, y int) (idx, slot int) {
+	bx, _, _, _ := g.browserRect()
+	top, visibleH := g.browserGridTop()
+	cols := g.browserCols()
+	if !g.isInGlyphBrowser(x, y) || y < top || y >= top+visibleH {
+		return -1, -1
+	}
+	col := (x - bx - browserPad) / browserPitchX()
+	if x < bx+browserPad || col >= cols {
+		return -1, -1
+	}
+	i := (y-top+g.glyphScroll)/browserPitchY()*cols + col
+	if i > len(g.glyphs) {
+		return -1, len(g.glyphs)
+	}
+	return i, i
+}
+
+// maxGlyphScroll - największe przewinięcie (wiersze z polem nowego znaku)
+func (g *Game) maxGlyphScroll() int {
+	_, visibleH := g.browserGridTop()
+	rows := (len(g.glyphs) + g.browserCols()) / g.browserCols()
+	return max(0, rows*browserPitchY()-visibleH)
+}
+
+// setGlyphScroll ustawia przewinięcie i pierwszy widoczny znak (glyphViewOfs)
+func (g *Game) setGlyphScroll(v int) {
+	g.glyphScroll = clampInt(v, 0, g.maxGlyphScroll())
+	g.glyphViewOfs = g.glyphScroll / browserPitchY() * g.browserCols()
+}
+
+// scrollBrowser przewija przeglądarkę kółkiem myszy
+func (g *Game) scrollBrowser(wy float64) {
+	g.setGlyphScroll(g.glyphScroll - int(wy*float64(browserPitchY())/2))
+}
+
+// scrollToGlyph przewija przeglądarkę tak, aby znak i był widoczny
+func (g *Game) scrollToGlyph(i int) {
+	_, visibleH := g.browserGridTop()
+	rowTop := i / g.browserCols() * browserPitchY()
+	s := g.glyphScroll
+	if rowTop < s {
+		s = rowTop
+	}
+	if rowTop+browserPitchY() > s+visibleH {
+		s = rowTop + browserPitchY() - visibleH
+	}
+	g.setGlyphScroll(s)
+}
+
+// glyphCode - kod znaku o indeksie i
+func (g *Game) glyphCode(i int) int {
+	return g.firstCode + i
+}
+
+// codeLabel - podpis miniatury: kod szesnastkowo i znak (tylko ASCII)
+func codeLabel(code int) string {
+	if code >= 0x21 && code <= 0x7E {
+		return fmt.Sprintf("%02X %c", code, code)
+	}
+	return fmt.Sprintf("%02X", code)
+}
+
+// findCode zamienia tekst pola "Kod" na indeks znaku
+func (g *Game) findCode(s string) (int, bool) {
+	code, err := parseCodeInput(s)
+	if err != nil {
+		return 0, false
+	}
+	i := code - g.firstCode
+	return i, i >= 0 && i < len(g.glyphs)
+}
+
+// onBrowserFilter - zmiana pola "Kod": przewinięcie do znaku
+func (g *Game) onBrowserFilter(s string) {
+	if i, ok := g.findCode(s); ok {
+		g.scrollToGlyph(i)
+	}
+}
+
+// onBrowserEnter - Enter w polu "Kod": wczytanie znaku
+func (g *Game) onBrowserEnter(s string) {
+	i, ok := g.findCode(s)
+	if !ok {
+		g.lastExport = "Brak znaku: " + s
+		return
+	}
+	g.selectGlyph(i)
+}
+
+// handleBrowserClick - LPM w przeglądarce: pole "Kod", wybór lub przeciąganie znaku
+func (g *Game) handleBrowserClick(x, y int) bool {
+	if !g.isInGlyphBrowser(x, y) {
+		return false
+	}
+	bx, by, _, _ := g.browserRect()
+	if x >= bx+44 && x < bx+144 && y >= by+4 && y < by+28 {
+		g.focusInput(&g.browserFilter)
+		return true
+	}
+	g.beginThumbDrag(x, y)
+	return true
+}
+
+// drawGlyphBrowser rysuje przeglądarkę znaków
+func (g *Game) drawGlyphBrowser(screen *ebiten.Image) {
+	bx, by, bw, bh := g.browserRect()
+	fillRect(screen, bx, by, bw, bh, color.RGBA{R: 0x0E, G: 0x0E, B: 0x10, A: 0xff})
+
+	// nagłówek: pole "Kod" i opis znaku pod kursorem / aktywnego
+	drawText(screen, "Kod:", bx+6, by+22)
+	g.browserFilter.draw(screen, bx+44, by+4, 100, 24, g.focus == &g.browserFilter)
+	drawText(screen, fmt.Sprintf("%d znaków", len(g.glyphs)), bx+154, by+22)
+
+	info := g.activeGlyph
+	if i, _ := g.thumbAt(ebiten.CursorPosition()); i >= 0 {
+		info = i
+	}
+	desc := fmt.Sprintf("Nowy znak %d (kod %s)", info, codeLabel(g.glyphCode(info)))
+	if info < len(g.glyphs) {
+		desc = fmt.Sprintf("Znak %d, kod %d (%s)", info, g.glyphCode(info), codeLabel(g.glyphCode(info)))
+		if g.mode == ModeWS2812B {
+			desc += fmt.Sprintf(", %.0f mA", g.glyphMA(g.glyphs[info]))
+		}
+	}
+	drawText(screen, desc, bx+6, by+44)
+
+	// siatka miniatur przycięta do widocznego obszaru
+	top, visibleH := g.browserGridTop()
+	view := screen.SubImage(image.Rect(bx, top, bx+bw, top+visibleH)).(*ebiten.Image)
+
+	cols := g.browserCols()
+	first := g.glyphScroll / browserPitchY() * cols
+	last := min(len(g.glyphs), (g.glyphScroll+visibleH)/browserPitchY()*cols+cols-1)
+	sw, sh := GridW*thumbScale(), GridH*thumbScale()
+	for i := first; i <= last; i++ {
+		x, y := g.thumbPos(i)
+
+		if i == g.activeGlyph {
+			fillRect(view, x-3, y-3, sw+6, sh+6, btnColorAct)
+		}
+		fillRect(view, x-1, y-1, sw+2, sh+2, color.RGBA{R: 0x22, G: 0x22, B: 0x26, A: 0xff})
+
+		if i == len(g.glyphs) {
+			// pole nowego znaku
+			drawText(view, "+", x+sw/2-4, y+sh/2+6)
+			continue
+		}
+		drawGlyphPreview(view, g.glyphs[i], x, y, thumbScale())
+		ebitenutil.DebugPrintAt(view, codeLabel(g.glyphCode(i)), x-2, y+sh+1)
+	}
+
+	// przeciąganie miniatury: znacznik miejsca upuszczenia
+	if g.thumbDrag >= 0 && g.thumbDrop != g.thumbDrag && g.thumbDrop != g.thumbDrag+1 {
+		x, y := g.thumbPos(g.thumbDrop)
+		fillRect(view, x-6, y-4, 3, sh+8, color.RGBA{R: 0xFF, G: 0xC0, B: 0x20, A: 0xff})
+	}
+
+	// pasek przewijania
+	if ms := g.maxGlyphScroll(); ms > 0 {
+		barH := max(16, visibleH*visibleH/(visibleH+ms))
+		barY := top + (visibleH-barH)*g.glyphScroll/ms
+		fillRect(screen, bx+bw-5, barY, 4, barH, color.RGBA{R: 0x50, G: 0x50, B: 0x5A, A: 0xff})
+	}
+}
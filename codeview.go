@@ -0,0 +1,317 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: codeview.go
+
+Podgląd kodu (g.previewText) jako przeglądarka tekstu:
+- dane bieżącego znaku w eksporcie są podświetlone (g.previewHL)
+- przewijanie kółkiem w pionie (g.textScroll, w wierszach),
+  Shift+kółko lub kółko poziome w bok (g.textScrollX, w znakach)
+- numery wierszy
+- zaznaczanie LPM z przeciąganiem, Ctrl+A zaznacza wszystko
+- podwójne kliknięcie / PPM na wierszu HEX otwiera edycję bajtów (hexedit.go)
+- "Kopiuj zazn." / Ctrl+C i "Kopiuj całość" do schowka programu (g.clipboard),
+  "Zapisz" zapisuje schowek do pliku
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// układ podglądu kodu (czcionka DebugPrint: 6 px na znak)
+const (
+	codeCharW   = 6
+	codeLineH   = 12
+	codeHeaderH = 28 // przyciski schowka
+	codeGutterW = 5 * codeCharW
+)
+
+// textPos - pozycja w tekście (wiersz, kolumna w znakach)
+type textPos struct {
+	line, col int
+}
+
+func (p textPos) before(q textPos) bool {
+	return p.line < q.line || p.line == q.line && p.col < q.col
+}
+
+// codeRect - obszar podglądu kodu
+func (g *Game) codeRect() (x, y, w, h int) {
+	x = EditorSize + 180 + 24
+	return x, 12, CanvasW - OptionsW - x - 12, g.textViewH
+}
+
+// codeTextArea - obszar samego tekstu (bez przycisków i numerów wierszy)
+func (g *Game) codeTextArea() (x, y, cols, rows int) {
+	cx, cy, cw, ch := g.codeRect()
+	x = cx + 6 + codeGutterW
+	y = cy + codeHeaderH
+	return x, y, (cx + cw - 6 - x) / codeCharW, (ch - codeHeaderH - 16) / codeLineH
+}
+
+func (g *Game) isInTextPreview(x, y int) bool {
+	cx, cy, cw, ch := g.codeRect()
+	return x >= cx && x < cx+cw && y >= cy && y < cy+ch
+}
+
+// codeLines - podgląd podzielony na wiersze (tabulatory jako 4 spacje);
+// podział jest pamiętany do następnej zmiany tekstu
+func (g *Game) codeLines() [][]rune {
+	if g.codeLinesSrc == g.previewText && g.codeLinesCache != nil {
+		return g.codeLinesCache
+	}
+	src := strings.Split(strings.ReplaceAll(g.previewText, "\t", "    "), "\n")
+	lines := make([][]rune, len(src))
+	for i, s := range src {
+		lines[i] = []rune(s)
+	}
+	g.codeLinesSrc, g.codeLinesCache = g.previewText, lines
+	return lines
+}
+
+// clampTextScroll utrzymuje przewinięcie w granicach tekstu
+func (g *Game) clampTextScroll(lines [][]rune) {
+	_, _, cols, rows := g.codeTextArea()
+	longest := 0
+	for _, ln := range lines {
+		longest = max(longest, len(ln))
+	}
+	g.textScroll = clampInt(g.textScroll, 0, max(0, len(lines)-rows))
+	g.textScrollX = clampInt(g.textScrollX, 0, max(0, longest-cols))
+}
+
+// scrollCode - kółko myszy nad podglądem
+func (g *Game) scrollCode(wx, wy float64) {
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		wx, wy = wy, 0
+	}
+	g.textScroll -= int(wy * 3)
+	g.textScrollX -= int(wx * 4)
+	g.clampTextScroll(g.codeLines())
+}
+
+// codePosAt zamienia punkt ekranu na pozycję w tekście (poza obszarem - przy krawędzi)
+func (g *Game) codePosAt(x, y int) textPos {
+	tx, ty, _, _ := g.codeTextArea()
+	lines := g.codeLines()
+	line := clampInt(g.textScroll+floorDiv(y-ty, codeLineH), 0, len(lines)-1)
+	col := g.textScrollX + floorDiv(x-tx+codeCharW/2, codeCharW)
+	return textPos{line, clampInt(col, 0, len(lines[line]))}
+}
+
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return (a - b + 1) / b
+	}
+	return a / b
+}
+
+// codeButtons - przyciski schowka nad tekstem
+func (g *Game) codeButtons() []uiButton {
+	cx, cy, _, _ := g.codeRect()
+	return []uiButton{
+		{cx + 6, cy + 4, 96, 20, "Kopiuj zazn.", g.copySelection},
+		{cx + 106, cy + 4, 104, 20, "Kopiuj całość", g.copyAll},
+		{cx + 214, cy + 4, 60, 20, "Zapisz", g.saveClipboardDialog},
+	}
+}
+
+// handleCodeViewClick - LPM w podglądzie: przyciski lub początek zaznaczenia
+func (g *Game) handleCodeViewClick(x, y int) bool {
+	if !g.isInTextPreview(x, y) {
+		return false
+	}
+	if clickButtons(g.codeButtons(), x, y) {
+		return true
+	}
+	if _, ty, _, _ := g.codeTextArea(); y >= ty {
+		p := g.codePosAt(x, y)
+		if g.codeDoubleClick(p) {
+			return true
+		}
+		g.textSelA, g.textSelB = p, p
+		g.textSelecting = true
+	}
+	return true
+}
+
+// dragCodeSelection - przeciąganie zaznaczenia; przy krawędzi tekst się przewija
+func (g *Game) dragCodeSelection(x, y int) {
+	tx, ty, cols, rows := g.codeTextArea()
+	switch {
+	case y < ty:
+		g.textScroll--
+	case y >= ty+rows*codeLineH:
+		g.textScroll++
+	}
+	switch {
+	case x < tx:
+		g.textScrollX--
+	case x >= tx+cols*codeCharW:
+		g.textScrollX++
+	}
+	g.clampTextScroll(g.codeLines())
+	g.textSelB = g.codePosAt(x, y)
+}
+
+// selection zwraca zaznaczenie w kolejności (ok = false gdy puste)
+func (g *Game) selection() (a, b textPos, ok bool) {
+	a, b = g.textSelA, g.textSelB
+	if b.before(a) {
+		a, b = b, a
+	}
+	return a, b, a != b
+}
+
+// selectAllCode - Ctrl+A
+func (g *Game) selectAllCode() {
+	lines := g.codeLines()
+	g.textSelA = textPos{}
+	g.textSelB = textPos{len(lines) - 1, len(lines[len(lines)-1])}
+}
+
+// selectedText zwraca zaznaczony fragment podglądu
+func (g *Game) selectedText() string {
+	a, b, ok := g.selection()
+	if !ok {
+		return ""
+	}
+	lines := g.codeLines()
+	if b.line >= len(lines) {
+		return ""
+	}
+	var sb strings.Builder
+	for l := a.line; l <= b.line; l++ {
+		ln := lines[l]
+		from, to := 0, len(ln)
+		if l == a.line {
+			from = min(a.col, len(ln))
+		}
+		if l == b.line {
+			to = min(b.col, len(ln))
+		}
+		sb.WriteString(string(ln[from:max(from, to)]))
+		if l < b.line {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// copySelection kopiuje zaznaczenie (bez zaznaczenia - całość) do schowka
+func (g *Game) copySelection() {
+	s := g.selectedText()
+	if s == "" {
+		g.copyAll()
+		return
+	}
+	g.clipboard = s
+	g.lastExport = fmt.Sprintf("Schowek: %d znaków", len([]rune(s)))
+}
+
+// copyAll kopiuje cały podgląd do schowka
+func (g *Game) copyAll() {
+	g.clipboard = g.previewText
+	g.lastExport = fmt.Sprintf("Schowek: %d znaków", len([]rune(g.clipboard)))
+}
+
+// saveClipboardDialog zapisuje schowek do pliku
+func (g *Game) saveClipboardDialog() {
+	if g.clipboard == "" {
+		g.lastExport = "Schowek jest pusty"
+		return
+	}
+	path, err := chooseFilename("schowek", "txt")
+	if err != nil {
+		return
+	}
+	if filepath.Ext(path) == "" {
+		path += ".txt"
+	}
+	if err := os.WriteFile(path, []byte(g.clipboard), 0o644); err != nil {
+		g.lastExport = "Błąd zapisu schowka"
+		return
+	}
+	g.lastExport = path
+}
+
+// drawCodeView rysuje podgląd kodu
+func (g *Game) drawCodeView(screen *ebiten.Image) {
+	cx, cy, cw, ch := g.codeRect()
+	fillRect(screen, cx, cy, cw, ch, color.RGBA{R: 0x0E, G: 0x0E, B: 0x10, A: 0xff})
+
+	for _, b := range g.codeButtons() {
+		b.draw(screen, btnColor)
+	}
+
+	lines := g.codeLines()
+	g.clampTextScroll(lines)
+	tx, ty, cols, rows := g.codeTextArea()
+	view := screen.SubImage(image.Rect(cx, ty, cx+cw, ty+rows*codeLineH)).(*ebiten.Image)
+	selA, selB, hasSel := g.selection()
+	selCol := color.RGBA{R: 0x2A, G: 0x50, B: 0x90, A: 0xff}
+
+	for r := 0; r < rows; r++ {
+		l := g.textScroll + r
+		if l >= len(lines) {
+			break
+		}
+		y := ty + r*codeLineH
+		ln := lines[l]
+
+		// dane bieżącego znaku w eksporcie
+		if l >= g.previewHL[0] && l <= g.previewHL[1] {
+			fillRect(view, tx-2, y, cols*codeCharW+4, codeLineH, color.RGBA{R: 0x24, G: 0x30, B: 0x1C, A: 0xff})
+		}
+
+		// zaznaczenie w tym wierszu
+		if hasSel && l >= selA.line && l <= selB.line {
+			from, to := 0, len(ln)+1 // +1: znak nowej linii
+			if l == selA.line {
+				from = selA.col
+			}
+			if l == selB.line {
+				to = selB.col
+			}
+			from = max(from-g.textScrollX, 0)
+			to = min(to-g.textScrollX, cols)
+			if to > from {
+				fillRect(view, tx+from*codeCharW, y, (to-from)*codeCharW, codeLineH, selCol)
+			}
+		}
+
+		ebitenutil.DebugPrintAt(view, fmt.Sprintf("%4d", l+1), cx+4, y-2)
+		if g.textScrollX < len(ln) {
+			vis := ln[g.textScrollX:]
+			if len(vis) > cols {
+				vis = vis[:cols]
+			}
+			ebitenutil.DebugPrintAt(view, string(vis), tx, y-2)
+		}
+	}
+
+	// oddzielenie numerów wierszy
+	fillRect(screen, tx-4, ty, 1, rows*codeLineH, color.RGBA{R: 0x33, G: 0x33, B: 0x3A, A: 0xff})
+
+	if g.textScroll > 0 || g.textScrollX > 0 || len(lines) > rows {
+		info := fmt.Sprintf("%d/%d", g.textScroll+1, len(lines))
+		if g.textScrollX > 0 {
+			info += fmt.Sprintf(" +%d", g.textScrollX)
+		}
+		ebitenutil.DebugPrintAt(screen, info, cx+cw-6-len(info)*codeCharW, cy+ch-14)
+	}
+
+	g.drawHexEdit(screen)
+}
@@ -0,0 +1,231 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: color_picker.go
+
+Zakładka "Kolor" panelu bocznego - dowolny kolor 24-bit do rysowania:
+- kwadrat nasycenie/jasność + pasek odcienia (HSV)
+- pole HEX (#RRGGBB + Enter)
+- pipeta (I): kliknięcie komórki siatki pobiera jej kolor
+- "Do palety": dopisuje bieżący kolor do palety
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// układ zakładki koloru
+const (
+	pickSVSize = 180 // kwadrat S/V
+	pickHueW   = 20  // pasek odcienia
+)
+
+// co jest aktualnie przeciągane w zakładce koloru
+const (
+	pickDragNone = iota
+	pickDragSV
+	pickDragHue
+)
+
+// pickerGeometry zwraca położenie kwadratu S/V, paska H i pola HEX
+func pickerGeometry() (svX, svY, hueX, hexY int) {
+	x0, y0, _, _ := sideRect()
+	svX = x0 + 8
+	svY = y0 + 28
+	hueX = svX + pickSVSize + 8
+	hexY = svY + pickSVSize + 10
+	return
+}
+
+func (g *Game) pickerButtons() []uiButton {
+	x0, _, _, _ := sideRect()
+	_, svY, hueX, hexY := pickerGeometry()
+	bx := hueX + pickHueW + 8
+	bw := x0 + OptionsW - 8 - bx
+	return []uiButton{
+		{bx, svY + 48, bw, 28, "Pipeta", func() { g.eyedropper = !g.eyedropper }},
+		{x0 + 136, hexY, OptionsW - 144, 26, "Do palety", g.pickToPalette},
+	}
+}
+
+// setPickColor ustawia kolor rysowania na dowolny kolor 24-bit
+func (g *Game) setPickColor(c color.RGBA) {
+	h, s, v := rgbToHSV(c)
+	g.pickHSV = [3]float64{h, s, v}
+	g.monoColor = trueColor(c)
+	g.pickHex.text = hexColor(c)
+}
+
+// applyPickHSV przelicza HSV z suwaków na kolor rysowania
+func (g *Game) applyPickHSV() {
+	c := hsvToRGB(g.pickHSV[0], g.pickHSV[1], g.pickHSV[2])
+	g.monoColor = trueColor(c)
+	g.pickHex.text = hexColor(c)
+}
+
+// pickToPalette dopisuje bieżący kolor na koniec palety
+func (g *Game) pickToPalette() {
+	if len(palette) >= MaxPalette {
+		g.lastExport = "Paleta pełna"
+		return
+	}
+	palette = append(palette, cellColor(g.monoColor))
+	g.selectColor(len(palette) - 1)
+	g.updatePreviewText()
+}
+
+// sampleCell - pipeta: pobiera kolor komórki jako kolor rysowania
+func (g *Game) sampleCell(v int) {
+	g.eyedropper = false
+	if v == 0 {
+		return
+	}
+	if isTrueColor(v) {
+		g.setPickColor(cellColor(v))
+		return
+	}
+	g.selectColor(v)
+}
+
+// handlePickerClick - kliknięcie w zakładce koloru
+func (g *Game) handlePickerClick(x, y, dir int) {
+	if dir < 0 {
+		return
+	}
+	svX, svY, hueX, hexY := pickerGeometry()
+
+	switch {
+	case x >= svX && x < svX+pickSVSize && y >= svY && y < svY+pickSVSize:
+		g.pickDrag = pickDragSV
+		g.dragPicker(x, y)
+	case x >= hueX && x < hueX+pickHueW && y >= svY && y < svY+pickSVSize:
+		g.pickDrag = pickDragHue
+		g.dragPicker(x, y)
+	case x >= svX && x < svX+120 && y >= hexY && y < hexY+26:
+		g.focusInput(&g.pickHex)
+	default:
+		clickButtons(g.pickerButtons(), x, y)
+	}
+}
+
+// dragPicker - przeciąganie w kwadracie S/V lub pasku odcienia
+func (g *Game) dragPicker(x, y int) {
+	svX, svY, _, _ := pickerGeometry()
+	fy := clampFloat(float64(y-svY)/float64(pickSVSize-1), 0, 1)
+	switch g.pickDrag {
+	case pickDragSV:
+		g.pickHSV[1] = clampFloat(float64(x-svX)/float64(pickSVSize-1), 0, 1)
+		g.pickHSV[2] = 1 - fy
+	case pickDragHue:
+		g.pickHSV[0] = fy * 359
+	default:
+		return
+	}
+	g.applyPickHSV()
+}
+
+// onPickHex - zatwierdzenie pola HEX
+func (g *Game) onPickHex(s string) {
+	c, err := parseHexColor(s)
+	if err != nil {
+		g.lastExport = "Zły kolor: " + s
+		return
+	}
+	g.setPickColor(c)
+}
+
+// svImage zwraca (z pamięci podręcznej) kwadrat S/V dla bieżącego odcienia
+func (g *Game) svImage() *ebiten.Image {
+	if g.pickSV != nil && g.pickSVHue == g.pickHSV[0] {
+		return g.pickSV
+	}
+	if g.pickSV == nil {
+		g.pickSV = ebiten.NewImage(pickSVSize, pickSVSize)
+	}
+	pix := make([]byte, pickSVSize*pickSVSize*4)
+	for y := 0; y < pickSVSize; y++ {
+		for x := 0; x < pickSVSize; x++ {
+			c := hsvToRGB(g.pickHSV[0], float64(x)/(pickSVSize-1), 1-float64(y)/(pickSVSize-1))
+			i := (y*pickSVSize + x) * 4
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = c.R, c.G, c.B, 0xff
+		}
+	}
+	g.pickSV.WritePixels(pix)
+	g.pickSVHue = g.pickHSV[0]
+	return g.pickSV
+}
+
+// hueImage - pasek odcienia (rysowany raz)
+func (g *Game) hueImage() *ebiten.Image {
+	if g.pickHue != nil {
+		return g.pickHue
+	}
+	g.pickHue = ebiten.NewImage(pickHueW, pickSVSize)
+	pix := make([]byte, pickHueW*pickSVSize*4)
+	for y := 0; y < pickSVSize; y++ {
+		c := hsvToRGB(float64(y)/(pickSVSize-1)*359, 1, 1)
+		for x := 0; x < pickHueW; x++ {
+			i := (y*pickHueW + x) * 4
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = c.R, c.G, c.B, 0xff
+		}
+	}
+	g.pickHue.WritePixels(pix)
+	return g.pickHue
+}
+
+// drawColorPicker rysuje zakładkę koloru
+func (g *Game) drawColorPicker(screen *ebiten.Image) {
+	x0, y0, _, _ := sideRect()
+	svX, svY, hueX, hexY := pickerGeometry()
+	cur := cellColor(g.monoColor)
+
+	drawText(screen, "Kolor rysowania (HSV)", x0+8, y0+20)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(svX), float64(svY))
+	screen.DrawImage(g.svImage(), op)
+	op = &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(hueX), float64(svY))
+	screen.DrawImage(g.hueImage(), op)
+
+	// znaczniki S/V i H
+	mx := svX + int(g.pickHSV[1]*(pickSVSize-1))
+	my := svY + int((1-g.pickHSV[2])*(pickSVSize-1))
+	fillRect(screen, mx-3, my-3, 7, 7, color.White)
+	fillRect(screen, mx-2, my-2, 5, 5, cur)
+	hy := svY + int(g.pickHSV[0]/359*(pickSVSize-1))
+	fillRect(screen, hueX-2, hy-1, pickHueW+4, 3, color.White)
+
+	// bieżący kolor
+	bx := hueX + pickHueW + 8
+	fillRect(screen, bx, svY, x0+OptionsW-8-bx, 40, cur)
+
+	if g.focus != &g.pickHex {
+		g.pickHex.text = hexColor(cur)
+	}
+	g.pickHex.draw(screen, svX, hexY, 120, 26, g.focus == &g.pickHex)
+
+	for i, b := range g.pickerButtons() {
+		col := color.Color(btnColor)
+		if i == 0 && g.eyedropper {
+			col = btnColorAct
+		}
+		b.draw(screen, col)
+	}
+
+	info := fmt.Sprintf("Wartość: %s", hexColor(cur))
+	if !isTrueColor(g.monoColor) {
+		info = fmt.Sprintf("Wartość: paleta %d", g.monoColor)
+	}
+	drawText(screen, info, x0+8, hexY+50)
+	if g.eyedropper {
+		drawText(screen, "Pipeta: kliknij komórkę siatki", x0+8, hexY+72)
+	}
+}
@@ -0,0 +1,347 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: effects.go
+
+Efekty wyświetlacza ("Efekt" w "Opcjach") - silnik tworzy sekwencję klatek
+z kolejnych znaków tekstu (pole "Tekst", pusty = bieżący znak):
+- przewijanie w pionie, miganie, wycieranie w lewo / w prawo,
+  losowe rozpuszczanie, nasuwanie z czterech stron
+- każdy efekt ma własne parametry (kierunek, liczba mignięć, ...),
+  wspólne są czas kroku i pauza na pełnym znaku
+- klatki idą do podglądu animacji (pasek 4 matryc) i przez serial
+  ("Matryce: podgląd animacji"), start / stop przyciskiem animacji
+- "Efekt: do klatek" kopiuje sekwencję do animacji z klatek (sprite.go),
+  skąd można ją wyeksportować do C
+
+"Przewijanie tekstu" to dotychczasowy przewijany tekst (marquee.go).
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// efekty
+const (
+	FxScroll = iota // przewijanie tekstu (marquee.go)
+	FxScrollV
+	FxBlink
+	FxWipe
+	FxDissolve
+	FxSlide
+
+	fxCount
+)
+
+// domyślne czasy efektów (ms)
+const (
+	DefaultFxStepMs = 60
+	DefaultFxHoldMs = 800
+)
+
+// fxParam - parametr efektu
+type fxParam struct {
+	label         string
+	def, min, max int
+	step          int
+	names         []string // nazwy wartości (np. kierunki), nil = liczba
+}
+
+// effect - opis efektu: parametry i generator klatek przejścia
+type effect struct {
+	name   string
+	params []fxParam
+	// build zwraca klatki pośrednie przejścia from -> to
+	// (bez końcowej klatki to, która dostaje pauzę)
+	build func(p []int, from, to Glyph) []Glyph
+}
+
+var effects = [fxCount]effect{
+	FxScroll: {name: "przewijanie tekstu"},
+	FxScrollV: {
+		name:   "przewijanie w pionie",
+		params: []fxParam{{label: "kierunek", max: 1, step: 1, names: []string{"w górę", "w dół"}}},
+		build:  fxScrollV,
+	},
+	FxBlink: {
+		name: "miganie",
+		params: []fxParam{
+			{label: "mignięcia", def: 3, min: 1, max: 10, step: 1},
+			{label: "faza mignięcia (kroki)", def: 4, min: 1, max: 20, step: 1},
+		},
+		build: fxBlink,
+	},
+	FxWipe: {
+		name:   "wycieranie",
+		params: []fxParam{{label: "kierunek", max: 1, step: 1, names: []string{"w prawo", "w lewo"}}},
+		build:  fxWipe,
+	},
+	FxDissolve: {
+		name: "rozpuszczanie",
+		params: []fxParam{
+			{label: "pikseli na krok", def: 4, min: 1, max: 32, step: 1},
+			{label: "ziarno losowania", def: 1, min: 1, max: 99, step: 1},
+		},
+		build: fxDissolve,
+	},
+	FxSlide: {
+		name:   "nasuwanie",
+		params: []fxParam{{label: "z kierunku", max: 3, step: 1, names: []string{"z prawej", "z lewej", "z dołu", "z góry"}}},
+		build:  fxSlide,
+	},
+}
+
+// defaultFxParams - domyślne parametry wszystkich efektów
+func defaultFxParams() [fxCount][]int {
+	var out [fxCount][]int
+	for i, e := range effects {
+		out[i] = make([]int, len(e.params))
+		for j, p := range e.params {
+			out[i][j] = p.def
+		}
+	}
+	return out
+}
+
+// fxScrollV - nowy znak wjeżdża od dołu (w górę) lub od góry (w dół), wypychając stary
+func fxScrollV(p []int, from, to Glyph) []Glyph {
+	var out []Glyph
+	for s := 1; s < GridH; s++ {
+		var f Glyph
+		for y := 0; y < GridH; y++ {
+			for x := 0; x < GridW; x++ {
+				switch {
+				case p[0] == 1 && y >= s: // w dół: stary znak zsuwa się
+					f[y][x] = from[y-s][x]
+				case p[0] == 1:
+					f[y][x] = to[y-s+GridH][x]
+				case y+s < GridH: // w górę: stary znak wyjeżdża górą
+					f[y][x] = from[y+s][x]
+				default:
+					f[y][x] = to[y+s-GridH][x]
+				}
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// fxBlink - znak gaśnie i zapala się p[0] razy, każda faza trwa p[1] kroków
+func fxBlink(p []int, _, to Glyph) []Glyph {
+	var out []Glyph
+	phase := func(gl Glyph) {
+		for k := 0; k < p[1]; k++ {
+			out = append(out, gl)
+		}
+	}
+	for i := 0; i < p[0]; i++ {
+		if i > 0 {
+			phase(to)
+		}
+		phase(Glyph{})
+	}
+	return out
+}
+
+// fxWipe - nowy znak odsłaniany kolumna po kolumnie
+func fxWipe(p []int, from, to Glyph) []Glyph {
+	var out []Glyph
+	for s := 1; s < GridW; s++ {
+		f := from
+		for y := 0; y < GridH; y++ {
+			for x := 0; x < GridW; x++ {
+				if (p[0] == 0 && x < s) || (p[0] == 1 && x >= GridW-s) {
+					f[y][x] = to[y][x]
+				}
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// fxDissolve - różniące się piksele zmieniane w losowej kolejności
+// (stałe ziarno - ta sama sekwencja przy każdym odtworzeniu)
+func fxDissolve(p []int, from, to Glyph) []Glyph {
+	var diff [][2]int
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			if from[y][x] != to[y][x] {
+				diff = append(diff, [2]int{x, y})
+			}
+		}
+	}
+	rnd := rand.New(rand.NewSource(int64(p[1])))
+	rnd.Shuffle(len(diff), func(i, j int) { diff[i], diff[j] = diff[j], diff[i] })
+
+	var out []Glyph
+	f := from
+	for i, d := range diff {
+		f[d[1]][d[0]] = to[d[1]][d[0]]
+		if (i+1)%p[0] == 0 && i+1 < len(diff) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// fxSlide - nowy znak nasuwa się na stary (stary stoi w miejscu)
+func fxSlide(p []int, from, to Glyph) []Glyph {
+	n := GridW
+	if p[0] >= 2 {
+		n = GridH
+	}
+	var out []Glyph
+	for s := 1; s < n; s++ {
+		f := from
+		for y := 0; y < GridH; y++ {
+			for x := 0; x < GridW; x++ {
+				switch p[0] {
+				case 0: // z prawej
+					if x >= GridW-s {
+						f[y][x] = to[y][x-(GridW-s)]
+					}
+				case 1: // z lewej
+					if x < s {
+						f[y][x] = to[y][x+GridW-s]
+					}
+				case 2: // z dołu
+					if y >= GridH-s {
+						f[y][x] = to[y-(GridH-s)][x]
+					}
+				case 3: // z góry
+					if y < s {
+						f[y][x] = to[y+GridH-s][x]
+					}
+				}
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// effectActive - wybrany efekt zamiast przewijanego tekstu
+func (g *Game) effectActive() bool {
+	return g.effect != FxScroll
+}
+
+// effectSequence - pełna sekwencja klatek efektu dla znaków tekstu;
+// pierwszy znak wchodzi z ostatniego (pętla), pojedynczy znak z pustej matrycy
+func (g *Game) effectSequence() []spriteFrame {
+	e := effects[g.effect]
+	src, _, _ := g.messageGlyphs()
+	from := Glyph{}
+	if len(src) > 1 {
+		from = src[len(src)-1]
+	}
+	var out []spriteFrame
+	for _, to := range src {
+		for _, f := range e.build(g.fxParams[g.effect], from, to) {
+			out = append(out, spriteFrame{Cells: f, Duration: g.fxStep})
+		}
+		out = append(out, spriteFrame{Cells: to, Duration: g.fxHold})
+		from = to
+	}
+	return out
+}
+
+// resetEffect unieważnia sekwencję (zmiana efektu, parametrów, tekstu)
+func (g *Game) resetEffect() {
+	g.fxFrames = nil
+	g.fxPos = 0
+	g.fxElapsed = 0
+}
+
+// effectFrame - bieżąca klatka efektu (sekwencja budowana w razie potrzeby)
+func (g *Game) effectFrame() Glyph {
+	if len(g.fxFrames) == 0 {
+		g.fxFrames = g.effectSequence()
+		g.fxPos = 0
+	}
+	return g.fxFrames[g.fxPos].Cells
+}
+
+// updateEffect odtwarza sekwencję efektu w pętli; po każdym przebiegu
+// sekwencja jest budowana od nowa (widać zmiany znaków)
+func (g *Game) updateEffect() {
+	g.effectFrame()
+	g.fxElapsed += 1000 / float64(ebiten.TPS())
+	for g.fxElapsed >= float64(g.fxFrames[g.fxPos].Duration) {
+		g.fxElapsed -= float64(g.fxFrames[g.fxPos].Duration)
+		g.fxPos++
+		if g.fxPos >= len(g.fxFrames) {
+			g.fxFrames = g.effectSequence()
+			g.fxPos = 0
+		}
+	}
+}
+
+// effectToSprite kopiuje sekwencję efektu do animacji z klatek
+func (g *Game) effectToSprite() {
+	if !g.effectActive() {
+		g.lastExport = "Wybierz efekt inny niż przewijanie tekstu"
+		return
+	}
+	frames := g.effectSequence()
+	g.sprite.Frames = frames
+	g.sprite.Mode = PlayLoop
+	g.spriteCur, g.spritePlayFrame, g.spriteScroll = 0, 0, 0
+	g.spritePlaying = false
+	if g.spriteMode() {
+		g.cells = frames[0].Cells
+	}
+	g.lastExport = fmt.Sprintf("Efekt: %d klatek w zakładce Klatki", len(frames))
+}
+
+// effectOptions - wybór efektu, wspólne czasy i parametry wybranego efektu
+func (g *Game) effectOptions() []option {
+	opts := []option{
+		{
+			label: "Efekt",
+			value: func() string { return effects[g.effect].name },
+			next:  func() { cycle(&g.effect, fxCount, 1); g.resetEffect() },
+			prev:  func() { cycle(&g.effect, fxCount, -1); g.resetEffect() },
+		},
+		{
+			label: "Efekt: krok",
+			value: func() string { return fmt.Sprintf("%d ms", g.fxStep) },
+			next:  func() { g.fxStep = clampInt(g.fxStep+10, MinFrameMs, MaxFrameMs); g.resetEffect() },
+			prev:  func() { g.fxStep = clampInt(g.fxStep-10, MinFrameMs, MaxFrameMs); g.resetEffect() },
+		},
+		{
+			label: "Efekt: pauza na znaku",
+			value: func() string { return fmt.Sprintf("%d ms", g.fxHold) },
+			next:  func() { g.fxHold = clampInt(g.fxHold+100, MinFrameMs, MaxFrameMs); g.resetEffect() },
+			prev:  func() { g.fxHold = clampInt(g.fxHold-100, MinFrameMs, MaxFrameMs); g.resetEffect() },
+		},
+	}
+	for j, p := range effects[g.effect].params {
+		v := &g.fxParams[g.effect][j]
+		opts = append(opts, option{
+			label: "Efekt: " + p.label,
+			value: func() string {
+				if p.names != nil {
+					return p.names[*v]
+				}
+				return fmt.Sprintf("%d", *v)
+			},
+			next: func() { *v = clampInt(*v+p.step, p.min, p.max); g.resetEffect() },
+			prev: func() { *v = clampInt(*v-p.step, p.min, p.max); g.resetEffect() },
+		})
+	}
+	return append(opts, option{
+		label: "Efekt: do klatek",
+		value: func() string { return "kopiuj" },
+		next:  g.effectToSprite,
+		prev:  g.effectToSprite,
+	})
+}
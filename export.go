@@ -13,6 +13,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"image"
+	"image/color"
 	"image/png"
 	"log"
 	"os"
@@ -23,15 +24,106 @@ import (
 // funkcje eksportu i generatory
 // -----------------------------
 
+// colorFormat opisuje kodowanie koloru piksela w eksporcie
+type colorFormat struct {
+	ctype  string // typ C jednego piksela
+	digits int    // liczba cyfr hex
+	encode func(c color.RGBA) uint32
+}
+
+// formaty kolorowe dostępne w exportMode
+var colorFormats = map[int]colorFormat{
+	ExportRGB:    {"uint16_t", 4, func(c color.RGBA) uint32 { return uint32(rgb565(c)) }},
+	ExportRGB888: {"uint32_t", 6, rgb888},
+	ExportRGB332: {"uint8_t", 2, func(c color.RGBA) uint32 { return uint32(rgb332(c)) }},
+	ExportRGB444: {"uint16_t", 3, func(c color.RGBA) uint32 { return uint32(rgb444(c)) }},
+	ExportBGR565: {"uint16_t", 4, func(c color.RGBA) uint32 { return uint32(bgr565(c)) }},
+}
+
+// kolejność formatów kolorowych przy przełączaniu
+var colorModes = []int{ExportRGB, ExportRGB888, ExportRGB332, ExportRGB444, ExportBGR565}
+
+// nextColorFormat zwraca sąsiedni format kolorowy (d = +1 / -1)
+func nextColorFormat(mode, d int) int {
+	i := 0
+	for j, m := range colorModes {
+		if m == mode {
+			i = j
+		}
+	}
+	cycle(&i, len(colorModes), d)
+	return colorModes[i]
+}
+
+// ExportOptions - ustawienia generatora C poza samym exportMode
+type ExportOptions struct {
+	Progmem        bool
+	IndexUsedOnly  bool // eksport indeksowany: tablica tylko z użytych kolorów
+	IndexColorMode int  // eksport indeksowany: format koloru tablicy palety
+
+	RowWords bool // 1-bit: wiersz szerszy niż 8 pikseli jako uint16 zamiast bajtów
+
+	Widths  []int // czcionka proporcjonalna: szerokości znaków (nil = stała)
+	Spacing int   // czcionka proporcjonalna: odstęp między znakami
+
+	Metrics *fontMetrics // nil = bez stałych metryki (np. animacja)
+	Kerning []kernPair   // posortowane pary kerningu
+
+	Symbol string // nazwa tablicy i prefiks stałych (puste = font)
+
+	WS2812Order      int // eksport WS2812: kolejność bajtów
+	WS2812Brightness int // eksport WS2812: jasność w %
+	WS2812Layout     WS2812Layout
+	WS2812Calib      *LEDCalibration // korekcja gamma/balansu, nil = bez korekcji
+}
+
+// exportOptions zbiera bieżące ustawienia eksportu z edytora
+func (g *Game) exportOptions(progmem bool) ExportOptions {
+	m := g.metrics()
+	o := ExportOptions{
+		Progmem:        progmem,
+		IndexUsedOnly:  g.indexUsedOnly,
+		IndexColorMode: g.indexColorMode,
+		RowWords:       g.rowWords,
+		Metrics:        &m,
+		Kerning:        g.kerning,
+
+		WS2812Order:      g.ws2812Order,
+		WS2812Brightness: g.ws2812Brightness,
+		WS2812Layout:     g.ws2812Layout,
+	}
+	if g.ws2812ExportCalib {
+		calib := g.ledCalib
+		o.WS2812Calib = &calib
+	}
+	if g.proportional {
+		o.Widths = g.advances()
+		o.Spacing = g.letterSpacing
+	}
+	return o
+}
+
+// symbol - nazwa tablicy (małe litery) i prefiks stałych (wielkie litery)
+func (o ExportOptions) symbol() (name, prefix string) {
+	name = o.Symbol
+	if name == "" {
+		name = "font"
+	}
+	return name, strings.ToUpper(name)
+}
+
+// hex formatuje zakodowany kolor z odpowiednią liczbą cyfr
+func (f colorFormat) hex(c color.RGBA) string {
+	return fmt.Sprintf("0x%0*X", f.digits, f.encode(c))
+}
+
 // eksport czystego C - zależnie od exportMode
 func (g *Game) exportC() error {
 
 	text := GenerateCFromGlyphs(
 		g.glyphs,
 		g.exportMode,
-		false)
-
-	g.previewText = text
+		g.exportOptions(false))
 
 	err := os.MkdirAll("export", os.ModePerm)
 	if err != nil {
@@ -53,11 +145,9 @@ func (g *Game) exportPROGMEM() error {
 	text := GenerateCFromGlyphs(
 		g.glyphs,
 		g.exportMode,
-		true,
+		g.exportOptions(true),
 	)
 
-	g.previewText = text
-
 	err := os.MkdirAll("export", os.ModePerm)
 	if err != nil {
 		return err
@@ -73,7 +163,7 @@ func (g *Game) exportPROGMEM() error {
 	return nil
 }
 
-// zapis 1-bit -> 1 bajt na wiersz (8 pikseli)
+// zapis 1-bit -> 1 liczba na wiersz (uint8 do 8 pikseli, szersze uint16)
 func (g *Game) saveC1bit(progmem bool) error {
 	var b bytes.Buffer
 
@@ -82,22 +172,19 @@ func (g *Game) saveC1bit(progmem bool) error {
 `)
 	}
 
-	b.WriteString("// 8x8 1-bit glyph\n")
+	b.WriteString(fmt.Sprintf("// %dx%d 1-bit glyph\n", GridW, GridH))
 
+	bits := rowBits1()
 	if progmem {
-		b.WriteString("const uint8_t glyph8x8[] PROGMEM = {\n")
+		b.WriteString(fmt.Sprintf("const %s glyph[] PROGMEM = {\n", ctypeBits(bits)))
 	} else {
-		b.WriteString("uint8_t glyph8x8[] = {\n")
+		b.WriteString(fmt.Sprintf("%s glyph[] = {\n", ctypeBits(bits)))
 	}
 
+	gl := Glyph(g.cells)
 	for y := 0; y < GridH; y++ {
-		var row uint8
-		for x := 0; x < GridW; x++ {
-			if g.cells[y][x] != 0 {
-				row |= 1 << (7 - x)
-			}
-		}
-		b.WriteString(fmt.Sprintf("  0x%02X, // %08b\n", row, row))
+		row := gl.Row1(y)
+		b.WriteString(fmt.Sprintf("  %s, // %0*b\n", hexDigits(row, bits), bits, row))
 	}
 
 	b.WriteString("};\n")
@@ -116,7 +203,7 @@ func (g *Game) saveC1bit(progmem bool) error {
 	return nil
 }
 
-// zapis 2-bit -> 2 bity na piksel -> 16 bit (uint16) na wiersz
+// zapis 2-bit -> 2 bity na piksel -> uint16 na wiersz (szersze znaki uint32)
 func (g *Game) saveC2bit(progmem bool) error {
 	var b bytes.Buffer
 
@@ -125,22 +212,19 @@ func (g *Game) saveC2bit(progmem bool) error {
 `)
 	}
 
-	b.WriteString("// 8x8 2-bit glyph (2 bity na piksel)\n")
+	b.WriteString(fmt.Sprintf("// %dx%d 2-bit glyph (2 bity na piksel)\n", GridW, GridH))
 
+	bits := rowBits2()
 	if progmem {
-		b.WriteString("const uint16_t glyph8x8[] PROGMEM = {\n")
+		b.WriteString(fmt.Sprintf("const %s glyph[] PROGMEM = {\n", ctypeBits(bits)))
 	} else {
-		b.WriteString("uint16_t glyph8x8[] = {\n")
+		b.WriteString(fmt.Sprintf("%s glyph[] = {\n", ctypeBits(bits)))
 	}
 
+	gl := Glyph(g.cells)
 	for y := 0; y < GridH; y++ {
-		var row uint16
-		for x := 0; x < GridW; x++ {
-			v := uint16(g.cells[y][x] % 4)
-			shift := uint((7 - x) * 2)
-			row |= v << shift
-		}
-		b.WriteString(fmt.Sprintf("  0x%04X, // %016b\n", row, row))
+		row := gl.Row2(y)
+		b.WriteString(fmt.Sprintf("  %s, // %0*b\n", hexDigits(row, bits), bits, row))
 	}
 
 	b.WriteString("};\n")
@@ -159,30 +243,33 @@ func (g *Game) saveC2bit(progmem bool) error {
 	return nil
 }
 
-// zapis RGB -> uint16_t RGB565 per pixel, zapis jako tablica [8][8]
+// zapis RGB -> piksel w formacie koloru z exportMode (domyślnie RGB565),
+// zapis jako tablica [wiersz][kolumna]
 func (g *Game) saveCRGB(progmem bool) error {
 	var b bytes.Buffer
 
+	cf, ok := colorFormats[g.exportMode]
+	if !ok {
+		cf = colorFormats[ExportRGB]
+	}
+
 	if progmem {
 		b.WriteString(`#include <avr/pgmspace.h>
 `)
 	}
 
-	b.WriteString("// 8x8 RGB565 glyph (uint16_t per pixel)\n")
+	b.WriteString(fmt.Sprintf("// %dx%d %s glyph (%s per pixel)\n", GridW, GridH, g.exportModeLabel(), cf.ctype))
 
 	if progmem {
-		b.WriteString("const uint16_t glyph8x8[8][8] PROGMEM = {\n")
+		b.WriteString(fmt.Sprintf("const %s glyph[%d][%d] PROGMEM = {\n", cf.ctype, GridH, GridW))
 	} else {
-		b.WriteString("uint16_t glyph8x8[8][8] = {\n")
+		b.WriteString(fmt.Sprintf("%s glyph[%d][%d] = {\n", cf.ctype, GridH, GridW))
 	}
 
 	for y := 0; y < GridH; y++ {
 		b.WriteString("  { ")
 		for x := 0; x < GridW; x++ {
-			idx := g.cells[y][x]
-			c := palette[idx]
-			rgb := rgb565(c)
-			b.WriteString(fmt.Sprintf("0x%04X", rgb))
+			b.WriteString(cf.hex(cellColor(g.cells[y][x])))
 			if x < GridW-1 {
 				b.WriteString(", ")
 			}
@@ -214,12 +301,7 @@ func (g *Game) exportPNG() error {
 	img := image.NewRGBA(image.Rect(0, 0, GridW, GridH))
 	for y := 0; y < GridH; y++ {
 		for x := 0; x < GridW; x++ {
-			idx := g.cells[y][x]
-			c := palette[0]
-			if idx >= 0 && idx < len(palette) {
-				c = palette[idx]
-			}
-			img.SetRGBA(x, y, c)
+			img.SetRGBA(x, y, cellColor(g.cells[y][x]))
 		}
 	}
 
@@ -256,13 +338,7 @@ func (g *Game) exportJSON() error {
 	for y := 0; y < GridH; y++ {
 		out[y] = make([]string, GridW)
 		for x := 0; x < GridW; x++ {
-			idx := g.cells[y][x]
-			if idx >= 0 && idx < len(palette) {
-				c := palette[idx]
-				out[y][x] = fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
-			} else {
-				out[y][x] = "#000000"
-			}
+			out[y][x] = hexColor(cellColor(g.cells[y][x]))
 		}
 	}
 
@@ -281,10 +357,36 @@ func (g *Game) exportJSON() error {
 }
 
 // -----------------------------
-// uaktualnienie tekstowego podglądu (HEX / BIN)
+// uaktualnienie tekstowego podglądu
 // -----------------------------
 
+// updatePreviewText odświeża podgląd: dokładny tekst eksportu całej czcionki
+// (bieżący znak podświetlony) albo HEX/BIN siatki (g.previewGrid)
 func (g *Game) updatePreviewText() {
+	if g.previewGrid {
+		g.updateGridPreview()
+		return
+	}
+
+	text, spans := generateC(g.glyphs, g.exportMode, g.exportOptions(g.previewProgmem))
+	g.previewText = text
+	g.previewHL = glyphSpan{-1, -1}
+	if !g.isNewGlyph() && g.activeGlyph < len(spans) {
+		g.previewHL = spans[g.activeGlyph]
+	}
+
+	// po zmianie znaku przewijamy podgląd do jego danych
+	if g.previewHLGlyph != g.activeGlyph {
+		g.previewHLGlyph = g.activeGlyph
+		if g.previewHL[0] >= 0 {
+			g.textScroll = g.previewHL[0] - 2
+		}
+	}
+}
+
+// updateGridPreview - HEX/BIN bieżącej siatki (edytowalny, hexedit.go)
+func (g *Game) updateGridPreview() {
+	g.previewHL = glyphSpan{-1, -1}
 	var sb strings.Builder
 
 	sb.WriteString("// Podglad wygenerowanej tablicy\n")
@@ -297,46 +399,80 @@ func (g *Game) updatePreviewText() {
 	switch g.exportMode {
 
 	case Export1Bit:
+		gl, bits := Glyph(g.cells), rowBits1()
 		for y := 0; y < GridH; y++ {
-			var row uint8
-			for x := 0; x < GridW; x++ {
-				if g.cells[y][x] != 0 {
-					row |= 1 << (7 - x)
-				}
-			}
+			row := gl.Row1(y)
 			sb.WriteString(fmt.Sprintf(
-				"ROW %d: 0x%02X  bin:%08b\n",
-				y, row, row,
+				"ROW %d: %s  bin:%0*b\n",
+				y, hexDigits(row, bits), bits, row,
 			))
 		}
 
 	case Export2Bit:
+		gl, bits := Glyph(g.cells), rowBits2()
 		for y := 0; y < GridH; y++ {
-			var row uint16
-			for x := 0; x < GridW; x++ {
-				v := uint16(g.cells[y][x] % 4)
-				shift := uint((7 - x) * 2)
-				row |= v << shift
-			}
+			row := gl.Row2(y)
 			sb.WriteString(fmt.Sprintf(
-				"ROW %d: 0x%04X  bin:%016b\n",
-				y, row, row,
+				"ROW %d: %s  bin:%0*b\n",
+				y, hexDigits(row, bits), bits, row,
 			))
 		}
 
-	case ExportRGB:
+	case ExportRGB, ExportRGB888, ExportRGB332, ExportRGB444, ExportBGR565:
+		cf := colorFormats[g.exportMode]
 		for y := 0; y < GridH; y++ {
 			for x := 0; x < GridW; x++ {
-				idx := g.cells[y][x]
-				c := palette[idx]
-				rgb := rgb565(c)
 				sb.WriteString(fmt.Sprintf(
-					"%02d,%02d: 0x%04X  ",
-					x, y, rgb,
+					"%02d,%02d: %s  ",
+					x, y, cf.hex(cellColor(g.cells[y][x])),
 				))
 			}
 			sb.WriteString("\n")
 		}
+
+	case ExportIndexed:
+		// tablica z tej samej listy znaków co eksport (writeIndexedC);
+		// niezapisane kolory siatki dostają najbliższy kolor z tablicy
+		table, merged := buildIndexTable(g.glyphs, g.indexUsedOnly)
+		bits := indexBits(len(table))
+		sb.WriteString(fmt.Sprintf("paleta: %d kolorów (zapisane znaki), %d bit/piksel\n", len(table), bits))
+		if merged > 0 {
+			sb.WriteString(fmt.Sprintf("uwaga: %d kolorów ponad %d -> najbliższe\n", merged, MaxPalette))
+		}
+		// wiersze nie muszą kończyć się na granicy bajtu (np. 5x7 przy
+		// 2/4 bitach) - bajty jako ciągły strumień, indeksy wierszami
+		for i, b := range packIndexed(Glyph(g.cells), table, bits) {
+			if i%8 == 0 {
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+				sb.WriteString(fmt.Sprintf("BAJT %02d:", i))
+			}
+			sb.WriteString(fmt.Sprintf(" 0x%02X", b))
+		}
+		sb.WriteString("\n\n")
+		for y := 0; y < GridH; y++ {
+			sb.WriteString(fmt.Sprintf("ROW %d: idx:", y))
+			for x := 0; x < GridW; x++ {
+				sb.WriteString(fmt.Sprintf(" %d", indexOf(table, g.cells[y][x])))
+			}
+			sb.WriteString("\n")
+		}
+
+	case ExportWS2812:
+		// bajty w kolejności fizycznej diod, 1 wiersz = szerokość paneli znaku
+		stream := ws2812Stream(Glyph(g.cells), g.exportOptions(false))
+		lw, _ := g.ws2812Layout.ForGlyph().Size()
+		sb.WriteString(fmt.Sprintf("kolejność: %s, jasność: %d%%\n", ws2812OrderName(g.ws2812Order), g.ws2812Brightness))
+		for led := 0; led < len(stream)/3; led++ {
+			if led%lw == 0 {
+				sb.WriteString(fmt.Sprintf("LED %02d:", led))
+			}
+			sb.WriteString(fmt.Sprintf(" %02X%02X%02X", stream[led*3], stream[led*3+1], stream[led*3+2]))
+			if led%lw == lw-1 {
+				sb.WriteString("\n")
+			}
+		}
 	}
 
 	g.previewText = sb.String()
@@ -347,17 +483,40 @@ func (g *Game) updatePreviewText() {
 // -----------------------------
 
 func (g *Game) exportModeLabel() string {
-	switch g.exportMode {
+	return exportModeName(g.exportMode)
+}
+
+func exportModeName(mode int) string {
+	switch mode {
 	case Export1Bit:
 		return "1-bit"
 	case Export2Bit:
 		return "2-bit"
 	case ExportRGB:
 		return "RGB565"
+	case ExportRGB888:
+		return "RGB888"
+	case ExportRGB332:
+		return "RGB332"
+	case ExportRGB444:
+		return "RGB444"
+	case ExportBGR565:
+		return "BGR565 swap"
+	case ExportIndexed:
+		return "Indeks"
+	case ExportWS2812:
+		return "WS2812"
 	}
 	return "?"
 }
 
+func ws2812OrderName(order int) string {
+	if order == WS2812OrderRGB {
+		return "RGB"
+	}
+	return "GRB"
+}
+
 func (g *Game) exportFormatLabel() string {
 	if g.exportFormat == 0 {
 		return "C"
@@ -365,54 +524,171 @@ func (g *Game) exportFormatLabel() string {
 	return "PROGMEM"
 }
 
-func GenerateCFromGlyphs(glyphs [][]byte, exportMode int, progmem bool) string {
+func GenerateCFromGlyphs(glyphs []Glyph, exportMode int, opts ExportOptions) string {
+	text, _ := generateC(glyphs, exportMode, opts)
+	return text
+}
+
+// glyphSpan - wiersze wygenerowanego tekstu z danymi jednego znaku [od, do]
+type glyphSpan [2]int
+
+// lineCounter liczy wiersze zapisane dotąd do b (przyrostowo)
+type lineCounter struct {
+	b        *strings.Builder
+	n, pos   int
+	spans    []glyphSpan
+	startRow int
+}
+
+func newLineCounter(b *strings.Builder, n int) *lineCounter {
+	return &lineCounter{b: b, spans: make([]glyphSpan, n)}
+}
+
+func (c *lineCounter) line() int {
+	s := c.b.String()
+	c.n += strings.Count(s[c.pos:], "\n")
+	c.pos = len(s)
+	return c.n
+}
+
+// begin / end oznaczają początek i koniec danych znaku i
+func (c *lineCounter) begin() { c.startRow = c.line() }
+func (c *lineCounter) end(i int) {
+	c.spans[i] = glyphSpan{c.startRow, c.line() - 1}
+}
+
+// generateC - tekst eksportu C oraz zakres wierszy każdego znaku (podgląd)
+func generateC(glyphs []Glyph, exportMode int, opts ExportOptions) (string, []glyphSpan) {
 	var b strings.Builder
+	lc := newLineCounter(&b, len(glyphs))
 
+	progmem := opts.Progmem
 	if progmem {
 		b.WriteString("#include <avr/pgmspace.h>\n\n")
 	}
+	name, prefix := opts.symbol()
 
 	b.WriteString("// Generated by Sun8x8 Font Generator\n\n")
+	b.WriteString(fmt.Sprintf("#define %s_WIDTH  %d\n", prefix, GridW))
+	b.WriteString(fmt.Sprintf("#define %s_HEIGHT %d\n", prefix, GridH))
+	if opts.Metrics != nil {
+		writeMetrics(&b, *opts.Metrics)
+	} else {
+		b.WriteString("\n")
+	}
 
 	n := len(glyphs) // liczba wygenerowanych znaków
 
+	pm := ""
+	if progmem {
+		pm = " PROGMEM"
+	}
+
 	switch exportMode {
 	case Export1Bit:
-		if progmem {
-			b.WriteString(fmt.Sprintf("const uint8_t font[%d][8] PROGMEM = {\n", n))
-		} else {
-			b.WriteString(fmt.Sprintf("const uint8_t font[%d][8] = {\n", n))
+		// wiersz do 8 pikseli = bajt; szerszy jako uint16 albo kolejne bajty
+		if rowBytes() > 1 && opts.RowWords {
+			bits := rowBits1()
+			b.WriteString(fmt.Sprintf("const %s %s[%d][%d]%s = {\n", ctypeBits(bits), name, n, GridH, pm))
+			writeRows(&b, glyphs, lc, bits, Glyph.Row1)
+			break
 		}
+		b.WriteString(fmt.Sprintf("const uint8_t %s[%d][%d]%s = {\n", name, n, GridH*rowBytes(), pm))
 
-		for _, g := range glyphs {
+		for i, g := range glyphs {
+			lc.begin()
 			b.WriteString("  { ")
-			for y := 0; y < 8; y++ {
-				if y > 0 {
+			for j, v := range g.Bits1() {
+				if j > 0 {
 					b.WriteString(", ")
 				}
-				b.WriteString(fmt.Sprintf("0x%02X", g[y]))
+				b.WriteString(fmt.Sprintf("0x%02X", v))
 			}
 			b.WriteString(" },\n")
+			lc.end(i)
 		}
-	case Export2Bit, ExportRGB:
-		if progmem {
-			b.WriteString(fmt.Sprintf("const uint16_t font[%d][8] PROGMEM = {\n", n))
-		} else {
-			b.WriteString(fmt.Sprintf("const uint16_t font[%d][8] = {\n", n))
+	case Export2Bit:
+		bits := rowBits2()
+		b.WriteString(fmt.Sprintf("const %s %s[%d][%d]%s = {\n", ctypeBits(bits), name, n, GridH, pm))
+		writeRows(&b, glyphs, lc, bits, Glyph.Row2)
+	case ExportRGB, ExportRGB888, ExportRGB332, ExportRGB444, ExportBGR565:
+		// kolor: [znak][wiersz][kolumna]
+		cf := colorFormats[exportMode]
+		b.WriteString(fmt.Sprintf("const %s %s[%d][%d][%d]%s = {\n", cf.ctype, name, n, GridH, GridW, pm))
+
+		for i, g := range glyphs {
+			lc.begin()
+			b.WriteString(fmt.Sprintf("  { // znak %d\n", i))
+			for y := 0; y < GridH; y++ {
+				b.WriteString("    { ")
+				for x := 0; x < GridW; x++ {
+					if x > 0 {
+						b.WriteString(", ")
+					}
+					b.WriteString(cf.hex(cellColor(g[y][x])))
+				}
+				b.WriteString(" },\n")
+			}
+			b.WriteString("  },\n")
+			lc.end(i)
 		}
-
-		for _, g := range glyphs {
-			b.WriteString("  { ")
-			for y := 0; y < 8; y++ {
-				if y > 0 {
-					b.WriteString(", ")
+	case ExportIndexed:
+		// osobny generator: tablica palety + upakowane indeksy + helper
+		writeIndexedC(&b, glyphs, opts, lc)
+		writeWidths(&b, opts)
+		writeKerning(&b, opts.Kerning, progmem)
+		return b.String(), lc.spans
+	case ExportWS2812:
+		// [znak][dioda*3] - gotowe do skopiowania do bufora show()
+		b.WriteString(fmt.Sprintf("// WS2812: %s, jasność %d%%\n", ws2812OrderName(opts.WS2812Order), opts.WS2812Brightness))
+		layout := opts.WS2812Layout.ForGlyph()
+		lw, _ := layout.Size()
+		b.WriteString(fmt.Sprintf("// panel: %s\n", layout.Describe()))
+		if c := opts.WS2812Calib; c != nil {
+			b.WriteString(fmt.Sprintf("// korekcja: gamma %.1f, balans R%d%% G%d%% B%d%%, maks. jasność %d%%\n",
+				c.Gamma, c.WhiteR, c.WhiteG, c.WhiteB, c.MaxBri))
+		}
+		b.WriteString(fmt.Sprintf("#define %s_NUM_LEDS %d\n\n", prefix, layout.NumLEDs()))
+		b.WriteString(fmt.Sprintf("const uint8_t %s[%d][%s_NUM_LEDS * 3]%s = {\n", name, n, prefix, pm))
+
+		for i, g := range glyphs {
+			lc.begin()
+			b.WriteString(fmt.Sprintf("  { // znak %d\n", i))
+			stream := ws2812Stream(g, opts)
+			for led := 0; led < len(stream)/3; led++ {
+				if led%lw == 0 {
+					b.WriteString("    ")
+				}
+				b.WriteString(fmt.Sprintf("0x%02X, 0x%02X, 0x%02X,", stream[led*3], stream[led*3+1], stream[led*3+2]))
+				if led%lw == lw-1 {
+					b.WriteString("\n")
+				} else {
+					b.WriteString(" ")
 				}
-				b.WriteString(fmt.Sprintf("0x%04X", uint16(g[y])))
 			}
-			b.WriteString(" },\n")
+			b.WriteString("  },\n")
+			lc.end(i)
 		}
 	}
 
 	b.WriteString("};\n")
-	return b.String()
+	writeWidths(&b, opts)
+	writeKerning(&b, opts.Kerning, progmem)
+	return b.String(), lc.spans
+}
+
+// writeRows dopisuje znaki jako wiersze liczb o szerokości bits
+func writeRows(b *strings.Builder, glyphs []Glyph, lc *lineCounter, bits int, row func(Glyph, int) uint32) {
+	for i, g := range glyphs {
+		lc.begin()
+		b.WriteString("  { ")
+		for y := 0; y < GridH; y++ {
+			if y > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(hexDigits(row(g, y), bits))
+		}
+		b.WriteString(" },\n")
+		lc.end(i)
+	}
 }
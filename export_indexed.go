@@ -0,0 +1,172 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: export_indexed.go
+
+Eksport indeksowany: zamiast pełnego koloru na piksel zapisujemy
+tablicę kolorów (paletę) i 2/4/8-bitowe indeksy do niej.
+Piksele pakowane wierszami, od lewej, najstarsze bity bajtu pierwsze.
+Tablica ma najwyżej MaxPalette (256) kolorów - nadmiarowe kolory 24-bit
+dostają najbliższy kolor z tablicy (liczba scalonych w komentarzu C).
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// buildIndexTable zwraca listę wartości komórek (indeksów palety), które
+// trafią do tablicy kolorów. OFF (0) jest zawsze pozycją 0. Tablica ma
+// najwyżej MaxPalette pozycji; merged - ile kolorów się nie zmieściło
+// (indexOf da im najbliższy kolor z tablicy).
+func buildIndexTable(glyphs []Glyph, usedOnly bool) (table []int, merged int) {
+	if !usedOnly {
+		table = make([]int, len(palette))
+		for i := range table {
+			table[i] = i
+		}
+		return table, 0
+	}
+
+	table = []int{0}
+	seen := map[int]bool{0: true}
+	for _, gl := range glyphs {
+		for y := 0; y < GridH; y++ {
+			for x := 0; x < GridW; x++ {
+				v := gl[y][x]
+				if seen[v] {
+					continue
+				}
+				seen[v] = true
+				if len(table) < MaxPalette {
+					table = append(table, v)
+				} else {
+					merged++
+				}
+			}
+		}
+	}
+	return table, merged
+}
+
+// indexBits - najmniejsza szerokość indeksu (2/4/8 bit) dla n kolorów
+func indexBits(n int) int {
+	switch {
+	case n <= 4:
+		return 2
+	case n <= 16:
+		return 4
+	}
+	return 8
+}
+
+// indexOf zwraca pozycję wartości komórki w tablicy; kolor spoza
+// tablicy (np. 24-bit przy pełnej palecie) dostaje najbliższy kolor
+func indexOf(table []int, v int) int {
+	for i, t := range table {
+		if t == v {
+			return i
+		}
+	}
+	if v == 0 {
+		return 0
+	}
+	cols := make([]color.RGBA, len(table))
+	for i, t := range table {
+		cols[i] = cellColor(t)
+	}
+	return nearestColor(cellColor(v), cols)
+}
+
+// packIndexed pakuje znak jako indeksy o szerokości bits
+func packIndexed(gl Glyph, table []int, bits int) []byte {
+	perByte := 8 / bits
+	out := make([]byte, (GridW*GridH+perByte-1)/perByte)
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			p := y*GridW + x
+			shift := uint(8 - bits - (p%perByte)*bits)
+			out[p/perByte] |= byte(indexOf(table, gl[y][x])) << shift
+		}
+	}
+	return out
+}
+
+// pgmRead - odczyt z PROGMEM dla danego typu C
+func pgmRead(ctype string) string {
+	switch ctype {
+	case "uint8_t":
+		return "pgm_read_byte"
+	case "uint32_t":
+		return "pgm_read_dword"
+	}
+	return "pgm_read_word"
+}
+
+// writeIndexedC dopisuje do b tablicę palety, znaki i funkcję font_pixel();
+// lc zapamiętuje wiersze znaków dla podglądu
+func writeIndexedC(b *strings.Builder, glyphs []Glyph, opts ExportOptions, lc *lineCounter) {
+	cf, ok := colorFormats[opts.IndexColorMode]
+	if !ok {
+		cf = colorFormats[ExportRGB]
+	}
+
+	table, merged := buildIndexTable(glyphs, opts.IndexUsedOnly)
+	bits := indexBits(len(table))
+	glyphBytes := (GridW*GridH*bits + 7) / 8
+
+	pm := ""
+	if opts.Progmem {
+		pm = " PROGMEM"
+	}
+	name, prefix := opts.symbol()
+
+	b.WriteString(fmt.Sprintf("#define %s_INDEX_BITS  %d\n", prefix, bits))
+	b.WriteString(fmt.Sprintf("#define %s_GLYPH_BYTES %d\n\n", prefix, glyphBytes))
+
+	// tablica kolorów
+	b.WriteString(fmt.Sprintf("// paleta: %s\n", exportModeName(opts.IndexColorMode)))
+	if merged > 0 {
+		b.WriteString(fmt.Sprintf("// uwaga: %d kolorów ponad %d zastąpiono najbliższymi\n", merged, MaxPalette))
+	}
+	b.WriteString(fmt.Sprintf("const %s %s_palette[%d]%s = {\n", cf.ctype, name, len(table), pm))
+	for i, v := range table {
+		b.WriteString(fmt.Sprintf("  %s, // %d\n", cf.hex(cellColor(v)), i))
+	}
+	b.WriteString("};\n\n")
+
+	// znaki
+	b.WriteString(fmt.Sprintf("const uint8_t %s[%d][%s_GLYPH_BYTES]%s = {\n", name, len(glyphs), prefix, pm))
+	for i, gl := range glyphs {
+		lc.begin()
+		b.WriteString("  { ")
+		for j, v := range packIndexed(gl, table, bits) {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(fmt.Sprintf("0x%02X", v))
+		}
+		b.WriteString(fmt.Sprintf(" }, // znak %d\n", i))
+		lc.end(i)
+	}
+	b.WriteString("};\n\n")
+
+	// helper: kolor piksela (x,y) znaku n
+	readByte := fmt.Sprintf("%s[n][p / (8 / %s_INDEX_BITS)]", name, prefix)
+	readColor := name + "_palette[i]"
+	if opts.Progmem {
+		readByte = "pgm_read_byte(&" + readByte + ")"
+		readColor = pgmRead(cf.ctype) + "(&" + readColor + ")"
+	}
+	b.WriteString(fmt.Sprintf("static inline %s %s_pixel(uint16_t n, uint8_t x, uint8_t y)\n{\n", cf.ctype, name))
+	b.WriteString(fmt.Sprintf("  uint16_t p = y * %d + x;\n", GridW))
+	b.WriteString(fmt.Sprintf("  uint8_t v = %s;\n", readByte))
+	b.WriteString(fmt.Sprintf("  uint8_t shift = 8 - %[1]s_INDEX_BITS - (p %% (8 / %[1]s_INDEX_BITS)) * %[1]s_INDEX_BITS;\n", prefix))
+	b.WriteString(fmt.Sprintf("  uint8_t i = (v >> shift) & ((1 << %s_INDEX_BITS) - 1);\n", prefix))
+	b.WriteString(fmt.Sprintf("  return %s;\n}\n", readColor))
+}
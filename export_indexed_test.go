@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+// TestPackIndexed - indeksy pakowane wierszami, najstarsze bity pierwsze
+func TestPackIndexed(t *testing.T) {
+	table := []int{0, 1, 2, 3}
+	tests := []struct {
+		w, h int
+		row  []int // komórki wierszami
+		bits int
+		want []byte
+	}{
+		{4, 2, []int{0, 1, 2, 3, 3, 2, 1, 0}, 2, []byte{0x1B, 0xE4}},
+		{4, 2, []int{0, 1, 2, 3, 3, 2, 1, 0}, 4, []byte{0x01, 0x23, 0x32, 0x10}},
+		{4, 2, []int{0, 1, 2, 3, 3, 2, 1, 0}, 8, []byte{0, 1, 2, 3, 3, 2, 1, 0}},
+		// niepełny ostatni bajt dopełniony zerami
+		{3, 1, []int{1, 2, 3}, 2, []byte{0x6C}},
+		{5, 1, []int{1, 2, 3, 1, 2}, 4, []byte{0x12, 0x31, 0x20}},
+		// wiersz nie kończy się na granicy bajtu
+		{3, 2, []int{1, 0, 0, 0, 0, 3}, 2, []byte{0x40, 0x30}},
+	}
+	for _, tt := range tests {
+		setGridSize(t, tt.w, tt.h)
+		var gl Glyph
+		for i, v := range tt.row {
+			gl[i/tt.w][i%tt.w] = v
+		}
+		got := packIndexed(gl, table, tt.bits)
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("%dx%d %d-bit: % X, want % X", tt.w, tt.h, tt.bits, got, tt.want)
+		}
+		if n := (tt.w*tt.h*tt.bits + 7) / 8; len(got) != n {
+			t.Errorf("%dx%d %d-bit: %d bajtów, want %d", tt.w, tt.h, tt.bits, len(got), n)
+		}
+	}
+}
+
+// TestBuildIndexTable - OFF na pozycji 0, kolejność pierwszego użycia,
+// najwyżej MaxPalette pozycji
+func TestBuildIndexTable(t *testing.T) {
+	setGridSize(t, 8, 8)
+
+	var a, b Glyph
+	a[0][0], a[0][1], a[1][0] = 5, 3, 5
+	b[2][2], b[3][3] = 3, trueColor(rgb(0x10, 0x20, 0x30))
+
+	table, merged := buildIndexTable([]Glyph{a, b}, true)
+	want := []int{0, 5, 3, trueColor(rgb(0x10, 0x20, 0x30))}
+	if !equalInts(table, want) || merged != 0 {
+		t.Errorf("usedOnly: %v merged %d, want %v merged 0", table, merged, want)
+	}
+
+	table, merged = buildIndexTable([]Glyph{a}, false)
+	if len(table) != len(palette) || merged != 0 {
+		t.Errorf("pełna paleta: %d pozycji merged %d, want %d", len(table), merged, len(palette))
+	}
+	for i, v := range table {
+		if v != i {
+			t.Fatalf("pełna paleta: table[%d] = %d", i, v)
+		}
+	}
+
+	// 5 znaków po 64 różne kolory 24-bit: 320 kolorów + OFF
+	glyphs := make([]Glyph, 5)
+	for i := range glyphs {
+		for p := 0; p < 64; p++ {
+			glyphs[i][p/8][p%8] = TrueColorFlag | (i*64 + p + 1)
+		}
+	}
+	table, merged = buildIndexTable(glyphs, true)
+	if len(table) != MaxPalette || merged != 321-MaxPalette {
+		t.Errorf("limit: %d pozycji merged %d, want %d merged %d", len(table), merged, MaxPalette, 321-MaxPalette)
+	}
+	if got := indexBits(len(table)); got != 8 {
+		t.Errorf("indexBits(%d) = %d, want 8", len(table), got)
+	}
+}
+
+// TestIndexBits - szerokość indeksu dla liczby kolorów
+func TestIndexBits(t *testing.T) {
+	for _, tt := range []struct{ n, bits int }{{1, 2}, {4, 2}, {5, 4}, {16, 4}, {17, 8}, {256, 8}} {
+		if got := indexBits(tt.n); got != tt.bits {
+			t.Errorf("indexBits(%d) = %d, want %d", tt.n, got, tt.bits)
+		}
+	}
+}
+
+func rgb(r, g, b uint8) color.RGBA { return color.RGBA{R: r, G: g, B: b, A: 0xff} }
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
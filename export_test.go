@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// setGridSize ustawia rozmiar znaku na czas testu
+func setGridSize(t *testing.T, w, h int) {
+	t.Helper()
+	oldW, oldH := GridW, GridH
+	GridW, GridH = w, h
+	t.Cleanup(func() { GridW, GridH = oldW, oldH })
+}
+
+// testGlyph - znak z colors kolorami palety (0..colors-1) rozłożonymi po siatce
+func testGlyph(colors, seed int) Glyph {
+	var gl Glyph
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			gl[y][x] = (x + y*GridW + seed) % colors
+		}
+	}
+	return gl
+}
+
+var hexLiteral = regexp.MustCompile(`0x([0-9A-F]+)`)
+
+// glyphLiterals - liczby hex z wierszy danych znaku i (bez komentarzy)
+func glyphLiterals(text string, span glyphSpan) []string {
+	lines := strings.Split(text, "\n")
+	var out []string
+	for _, ln := range lines[span[0] : span[1]+1] {
+		if i := strings.Index(ln, "//"); i >= 0 {
+			ln = ln[:i]
+		}
+		for _, m := range hexLiteral.FindAllStringSubmatch(ln, -1) {
+			out = append(out, m[1])
+		}
+	}
+	return out
+}
+
+// TestGenerateCSizes - liczba elementów danych znaku i ich szerokość
+// dla rozmiarów innych niż 8x8 w każdym trybie eksportu
+func TestGenerateCSizes(t *testing.T) {
+	layout := defaultWS2812Layout
+	tests := []struct {
+		w, h   int
+		mode   int
+		colors int  // liczba użytych kolorów palety
+		words  bool // 1-bit: wiersze jako uint16
+		elems  int  // elementów na znak
+		digits int  // cyfr hex w elemencie
+	}{
+		// 5x7: wiersz 1-bit mieści się w bajcie, 2-bit (10 bitów) w uint16
+		{5, 7, Export1Bit, 2, false, 7, 2},
+		{5, 7, Export1Bit, 2, true, 7, 2},
+		{5, 7, Export2Bit, 4, false, 7, 4},
+		{5, 7, ExportRGB, 4, false, 35, 4},
+		{5, 7, ExportRGB888, 4, false, 35, 6},
+		{5, 7, ExportRGB332, 4, false, 35, 2},
+		{5, 7, ExportRGB444, 4, false, 35, 3},
+		{5, 7, ExportBGR565, 4, false, 35, 4},
+		{5, 7, ExportIndexed, 4, false, (35*2 + 7) / 8, 2},
+		{5, 7, ExportIndexed, 5, false, (35*4 + 7) / 8, 2},
+		{5, 7, ExportWS2812, 4, false, 64 * 3, 2},
+
+		// 8x16: wiersz 1-bit = bajt, 2-bit = uint16, dwa panele WS2812 w pionie
+		{8, 16, Export1Bit, 2, false, 16, 2},
+		{8, 16, Export2Bit, 4, false, 16, 4},
+		{8, 16, ExportRGB, 4, false, 128, 4},
+		{8, 16, ExportRGB888, 4, false, 128, 6},
+		{8, 16, ExportRGB332, 4, false, 128, 2},
+		{8, 16, ExportRGB444, 4, false, 128, 3},
+		{8, 16, ExportBGR565, 4, false, 128, 4},
+		{8, 16, ExportIndexed, 4, false, (128*2 + 7) / 8, 2},
+		{8, 16, ExportWS2812, 4, false, 128 * 3, 2},
+
+		// 16x16: wiersz 1-bit = 2 bajty albo uint16, 2-bit = uint32
+		{16, 16, Export1Bit, 2, false, 16 * 2, 2},
+		{16, 16, Export1Bit, 2, true, 16, 4},
+		{16, 16, Export2Bit, 4, false, 16, 8},
+		{16, 16, ExportRGB, 4, false, 256, 4},
+		{16, 16, ExportRGB888, 4, false, 256, 6},
+		{16, 16, ExportRGB332, 4, false, 256, 2},
+		{16, 16, ExportRGB444, 4, false, 256, 3},
+		{16, 16, ExportBGR565, 4, false, 256, 4},
+		{16, 16, ExportIndexed, 4, false, (256*2 + 7) / 8, 2},
+		{16, 16, ExportIndexed, 17, false, 256, 2},
+		{16, 16, ExportWS2812, 4, false, 256 * 3, 2},
+	}
+	for _, tt := range tests {
+		name := fmt.Sprintf("%dx%d/%s/%dkol", tt.w, tt.h, exportModeName(tt.mode), tt.colors)
+		if tt.words {
+			name += "/uint16"
+		}
+		t.Run(name, func(t *testing.T) {
+			setGridSize(t, tt.w, tt.h)
+			glyphs := []Glyph{testGlyph(tt.colors, 0), testGlyph(tt.colors, 1)}
+			opts := ExportOptions{
+				IndexUsedOnly:    true,
+				IndexColorMode:   ExportRGB,
+				RowWords:         tt.words,
+				WS2812Brightness: 100,
+				WS2812Layout:     layout,
+			}
+			text, spans := generateC(glyphs, tt.mode, opts)
+			if len(spans) != len(glyphs) {
+				t.Fatalf("spans = %d, want %d", len(spans), len(glyphs))
+			}
+			for i, span := range spans {
+				lits := glyphLiterals(text, span)
+				if len(lits) != tt.elems {
+					t.Errorf("znak %d: %d elementów, want %d", i, len(lits), tt.elems)
+				}
+				for _, l := range lits {
+					if len(l) != tt.digits {
+						t.Errorf("znak %d: element 0x%s ma %d cyfr, want %d", i, l, len(l), tt.digits)
+						break
+					}
+				}
+			}
+		})
+	}
+}
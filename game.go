@@ -12,17 +12,22 @@ import (
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
-// siatka 8x8 edytora znaków
+// siatka edytora znaków: rozmiar znaku GridW x GridH (glyphsize.go)
+// do MaxGridW x MaxGridH, siatka zawsze zajmuje EditorSize x EditorSize
 const (
-	GridW    = 8
-	GridH    = 8
-	CellSize = 28
-	CanvasW  = GridW*CellSize + 500
-	CanvasH  = GridH*CellSize + 400
+	MaxGridW   = 16
+	MaxGridH   = 16
+	EditorSize = 8 * 28
+	CanvasW    = EditorSize + 500 + OptionsW
+	CanvasH    = EditorSize + 400
 )
 
+// fizyczna matryca LED (MAX7219) sterowana przez serial
+const MatrixSize = 8
+
 // tryby edycji
 const (
 	ModeMono = iota
@@ -31,21 +36,29 @@ const (
 	ModeWS2812B
 )
 
-// eksport: bit-depth
+// eksport: bit-depth / format koloru
 const (
 	Export1Bit = iota
 	Export2Bit
-	ExportRGB
+	ExportRGB // RGB565
+	ExportRGB888
+	ExportRGB332
+	ExportRGB444
+	ExportBGR565  // BGR565 z zamienionymi bajtami (ST7735/ILI9341 DMA)
+	ExportIndexed // indeksy do tablicy palety, 2/4/8 bit na piksel
+	ExportWS2812  // strumień bajtów GRB/RGB w kolejności diod
+
+	exportModeCount
 )
 
 const AnimCellScale = 0.25
 
 // Game struktura edytora
 type Game struct {
-	cells [GridH][GridW]int
+	cells [MaxGridH][MaxGridW]int
 
 	mode      int
-	monoColor int
+	monoColor int // kolor rysowania: indeks palety lub kolor 24-bit (TrueColorFlag)
 	twoA      int
 	twoB      int
 
@@ -57,11 +70,119 @@ type Game struct {
 	lastExport   string
 	previewText  string
 
+	// eksport indeksowany
+	indexUsedOnly  bool // tablica tylko z użytych kolorów
+	indexColorMode int  // format koloru w tablicy palety
+
+	// 1-bit: wiersze szersze niż 8 pikseli jako uint16 (zamiast kolejnych bajtów)
+	rowWords bool
+
+	// czcionka proporcjonalna (proportional.go)
+	proportional  bool
+	letterSpacing int   // odstęp między znakami (kolumny)
+	glyphAdv      []int // ręczna szerokość znaku, 0 = auto; równoległa do glyphs
+
+	// metryka i kerning (metrics.go)
+	ascent      int
+	descent     int
+	showMetrics bool       // linie metryki na siatce
+	kerning     []kernPair // posortowane wg (Left, Right)
+	kernLeft    textInput
+	kernRight   textInput
+	kernValue   int
+	kernScroll  int
+
+	// eksport WS2812
+	ws2812Order      int // WS2812OrderGRB / WS2812OrderRGB
+	ws2812Brightness int // jasność w % (0..100)
+	ws2812Layout     WS2812Layout
+
+	// kalibracja diod (serial i opcjonalnie eksport)
+	ledCalib          LEDCalibration
+	ws2812ExportCalib bool
+
+	// zasilanie WS2812: szacowanie prądu i limiter
+	power PowerModel
+
+	// panel boczny
+	sideTab   int
+	optScroll int
+
+	// edytor palety
+	palSel    int        // wybrany kolor
+	palScroll int        // przewinięcie próbek (wiersze)
+	palDrag   int        // chwycony suwak edycji, -1 = brak
+	palHSV    [3]float64 // H/S/V wybranego koloru (H nie ginie przy S = 0)
+
+	projectPath string // ostatnio zapisany / wczytany projekt
+
+	// wybór koloru 24-bit i pipeta
+	pickHSV    [3]float64
+	pickDrag   int
+	pickHex    textInput
+	pickSV     *ebiten.Image // kwadrat S/V (pamięć podręczna)
+	pickSVHue  float64       // odcień, dla którego narysowano pickSV
+	pickHue    *ebiten.Image // pasek odcienia
+	eyedropper bool
+
+	focus *textInput // aktywne pole tekstowe
+
+	// narzędzia rysowania (tools.go)
+	tool        int
+	shapeFilled bool
+	stroke      stroke
+
+	// przekształcenia znaku (transform.go)
+	shiftWrap bool
+
+	// operacje na zestawie znaków (batch.go)
+	firstCode   int // kod pierwszego znaku listy
+	baseline    int // wiersz linii bazowej (metrics.go)
+	batchPad    int // margines z lewej (kolumny)
+	batchByCode bool
+	batchFrom   textInput
+	batchTo     textInput
+
+	// historia cofania (history.go)
+	undoSeq   int
+	undoDepth int
+	glyphHist []*undoStack // historia siatki każdego znaku, równoległa do glyphs
+	newHist   undoStack    // historia nowego (nie zapisanego) znaku
+	listHist  undoStack    // historia operacji na liście znaków
+
 	// animacja
-	animX       float64
+	animX       float64 // kolumna początku przewijanego tekstu (marquee.go)
 	animRunning bool
 	animSpeed   float64
 	animDir     int
+	message     textInput // przewijany tekst
+	marqueeHW   bool      // matryce przez serial pokazują podgląd animacji
+	marqueeStep float64   // ułamek kolumny do następnego kroku
+
+	// efekty wyświetlacza (effects.go)
+	effect    int
+	fxParams  [fxCount][]int
+	fxStep    int // ms na klatkę przejścia
+	fxHold    int // ms na pełnym znaku
+	fxFrames  []spriteFrame
+	fxPos     int
+	fxElapsed float64
+
+	// onion skin (onion.go)
+	onion      int // OnionOff / OnionPrev / OnionNext / OnionBoth
+	onionAlpha int // krycie duchów w %
+
+	// animacja z klatek (sprite.go)
+	sprite          spriteDoc
+	spriteCur       int   // edytowana klatka
+	spriteEdit      bool  // siatka edytuje klatkę (tryb "Edycja klatek")
+	spriteSaved     Glyph // siatka znaku na czas edycji klatek
+	spriteScroll    int   // pierwszy widoczny rząd miniatur
+	spritePlaying   bool
+	spritePlayFrame int
+	spritePlayDir   int     // ping-pong: +1 / -1
+	spriteElapsed   float64 // ms w bieżącej klatce
+	spriteStream    bool    // matryce przez serial pokazują animację
 
 	// suwak
 	sliderX       int
@@ -80,24 +201,47 @@ type Game struct {
 	colorSliderGrabbed bool
 
 	// font builder
-	glyphs     [][]byte
+	glyphs     []Glyph
 	glyphIndex int
 
-	// --- scroll glyphów ---
-	glyphScroll int
-	glyphViewH  int
-
-	// --- scroll tekstu preview ---
-	textScroll int
-	textViewH  int
+	// --- scroll glyphów (przeglądarka znaków, browser.go) ---
+	glyphScroll   int // przewinięcie w pikselach
+	glyphViewH    int // wysokość przeglądarki
+	browserFilter textInput
+
+	// --- scroll tekstu preview (codeview.go) ---
+	textScroll     int // pierwszy widoczny wiersz
+	textScrollX    int // przesunięcie w poziomie (znaki)
+	textViewH      int
+	textSelA       textPos
+	textSelB       textPos
+	textSelecting  bool
+	codeLinesSrc   string
+	codeLinesCache [][]rune
+	clipboard      string // schowek programu
+
+	// podgląd: eksport całej czcionki lub HEX/BIN siatki
+	previewGrid    bool
+	previewProgmem bool
+	previewHL      glyphSpan // wiersze podświetlonego znaku (-1 = brak)
+	previewHLGlyph int
+
+	// edycja bajtów w podglądzie (hexedit.go)
+	hexEdit       textInput
+	hexTarget     hexEditTarget
+	codeClickLine int
+	codeClickTime time.Time
 
 	cellScroll int // scroll siatki edytora
 
 	serialStatus string
 
-	activeGlyph   int      // aktualnie wybrany znak
-	glyphViewOfs  int      // offset podglądu (0,1,2... → okno 8 znaków)
-	displayGlyphs [][]byte // max 4 glyphy po 8 bajtów
+	activeGlyph   int     // aktualnie wybrany znak (len(glyphs) = nowy znak)
+	glyphViewOfs  int     // pierwszy znak widoczny w przeglądarce
+	displayGlyphs []Glyph // max 3 zapisane glyphy dla matryc M1–M3
+	displayIdx    []int   // indeksy znaków z displayGlyphs
+	thumbDrag     int     // przeciągana miniatura (-1 = brak)
+	thumbDrop     int     // miejsce upuszczenia miniatury
 }
 
 func NewGame() *Game {
@@ -108,36 +252,77 @@ func NewGame() *Game {
 	g.twoA = 2
 	g.twoB = 3
 	g.exportMode = Export1Bit
+	g.indexUsedOnly = true
+	g.indexColorMode = ExportRGB
+	g.ws2812Order = WS2812OrderGRB
+	g.ws2812Brightness = 100
+	g.ws2812Layout = defaultWS2812Layout
+	g.ledCalib = defaultLEDCalibration
+	g.power = defaultPowerModel
 
 	g.sliderX = 12
-	g.sliderY = GridH*CellSize + 260
+	g.sliderY = EditorSize + 260
 	g.sliderW = 200
 	g.sliderH = 14
 	g.sliderValue = 0.5
 
 	g.animSpeed = 0.5
 	g.animDir = -1
+	g.message = textInput{maxLen: 64, onChange: func(string) { g.resetEffect() }}
+	g.fxParams = defaultFxParams()
+	g.fxStep, g.fxHold = DefaultFxStepMs, DefaultFxHoldMs
+	g.sprite = newSpriteDoc()
+	g.onionAlpha = DefaultOnionAlpha
 
 	// SUWAK: kolor mono
 	g.colorSliderX = 12
-	g.colorSliderY = GridH*CellSize + 68 + 32 + 8 + 10 // btnY + btnH + odstęp między przyciskami + dodatkowe pixele dla labela
+	g.colorSliderY = EditorSize + 68 + 32 + 8 + 10 // btnY + btnH + odstęp między przyciskami + dodatkowe pixele dla labela
 	g.colorSliderW = 200
 	g.colorSliderH = 14
 	g.colorSliderValue = float64(g.monoColor) / float64(len(palette)-1)
 
+	g.palDrag = -1
+	g.pickHex = textInput{maxLen: 9, onEnter: g.onPickHex}
+	g.firstCode = DefaultFirstCode
+	g.baseline = GridH - 2
+	g.defaultMetrics()
+	g.showMetrics = true
+	g.kernLeft = textInput{maxLen: 8, onChange: g.onKernInput}
+	g.kernRight = textInput{maxLen: 8, onChange: g.onKernInput}
+	g.batchPad = 1
+	g.letterSpacing = DefaultLetterSpacing
+	g.batchFrom = textInput{maxLen: 8}
+	g.batchTo = textInput{maxLen: 8}
+	g.selectColor(g.monoColor)
+
 	// ------ tutaj inicjalizacja serial -------
-	//matrixSerial = NewSerialMatrix("COM13", GridW, GridH)
-	port := detectSerialPort()
-	matrixSerial = NewSerialMatrix(port, GridW, GridH)
+	//matrixSerial = NewSerialMatrix("COM13", PicoMAX7219, MarqueeCols, MatrixSize)
+	port, kind := detectSerialPort()
+	matrixSerial = NewSerialMatrix(port, kind, MarqueeCols, MatrixSize)
 
 	if matrixSerial != nil {
-		g.serialStatus = "Podłączono do: " + port
+		g.serialStatus = "Podłączono do: " + port + picoKindName(kind)
 	} else {
 		g.serialStatus = "Brak połączenia z Pico"
 	}
 
-	g.activeGlyph = -1
+	g.activeGlyph = 0
 	g.glyphViewOfs = 0
+	g.thumbDrag = -1
+
+	// podgląd tekstu nad przeglądarką znaków
+	g.textViewH = 250
+	g.glyphViewH = CanvasH - 12 - g.textViewH - 12 - 64
+	g.browserFilter = textInput{maxLen: 8, onChange: g.onBrowserFilter, onEnter: g.onBrowserEnter}
+	g.hexEdit = textInput{
+		maxLen:   20,
+		onEnter:  g.applyHexEdit,
+		validate: func(s string) error { _, err := g.parseHexValue(s); return err },
+	}
+	g.codeClickLine = -1
+	g.previewHL = glyphSpan{-1, -1}
+	g.previewHLGlyph = -2
+	g.undoDepth = DefaultUndoDepth
 
 	return g
 }
@@ -152,6 +337,7 @@ func (g *Game) Update() error {
 	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
 		if !g.mouseDown {
 			g.mouseDown = true
+			g.focus = nil // kliknięcie kończy edycję pola tekstowego
 			// sprawdzanie wszystkich suwaków
 			if !g.handleSlider(x, y) { // animacja
 				// NOWY SUWAK: kolor mono
@@ -184,21 +370,58 @@ func (g *Game) Update() error {
 				}
 				g.monoColor = int(g.colorSliderValue * float64(len(palette)-1))
 			}
+			if g.palDrag >= 0 {
+				g.dragPaletteSlider(x)
+			}
+			if g.pickDrag != pickDragNone {
+				g.dragPicker(x, y)
+			}
+			if g.thumbDrag >= 0 {
+				g.dragThumb(x, y)
+			}
+			if g.textSelecting {
+				g.dragCodeSelection(x, y)
+			}
 		}
 	} else {
+		g.textSelecting = false
+		if g.thumbDrag >= 0 {
+			g.endThumbDrag()
+		}
 		g.mouseDown = false
 		g.sliderGrabbed = false
 		g.colorSliderGrabbed = false // "przyklejenie" suwaka koloru
+		g.palDrag = -1
+		g.pickDrag = pickDragNone
+	}
+
+	// PPM
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		g.handleRightClick(x, y)
+	}
+
+	// rysowanie z przeciąganiem
+	g.updateStroke(x, y)
+
+	// kółko myszy
+	if wx, wy := ebiten.Wheel(); wx != 0 || wy != 0 {
+		if g.isInGlyphBrowser(x, y) {
+			g.scrollBrowser(wy)
+		} else if g.isInTextPreview(x, y) {
+			g.scrollCode(wx, wy)
+		} else {
+			g.handleSideWheel(x, y, wy)
+		}
 	}
 
 	// ---- po obsłudze kliknięć wysyłamy ramkę do matrycy ----
 
 	if matrixSerial != nil {
 		var err error
-		if g.mode == ModeWS2812B {
-			err = matrixSerial.SendFrame(g.buildWS2812Frame())
+		if matrixSerial.WS2812() {
+			err = matrixSerial.SendWS2812(g.buildWS2812Frame())
 		} else {
-			err = matrixSerial.SendFrame(g.buildDisplayFrame())
+			err = matrixSerial.SendFrame(g.buildSerialFrame())
 		}
 		if err != nil {
 			matrixSerial.Close()
@@ -209,11 +432,11 @@ func (g *Game) Update() error {
 
 	// jeśli brak połączenia, próbuj reconnect
 	if matrixSerial == nil {
-		port := detectSerialPort() // twoja funkcja wykrywająca COM
+		port, kind := detectSerialPort() // twoja funkcja wykrywająca COM
 		if port != "" {
-			matrixSerial = NewSerialMatrix(port, GridW, GridH)
+			matrixSerial = NewSerialMatrix(port, kind, MarqueeCols, MatrixSize)
 			if matrixSerial != nil {
-				g.serialStatus = "Podłączono " + port
+				g.serialStatus = "Podłączono " + port + picoKindName(kind)
 			} else {
 				g.serialStatus = "Nie można otworzyć portu"
 			}
@@ -222,13 +445,94 @@ func (g *Game) Update() error {
 		}
 	}
 
+	// aktywne pole tekstowe przejmuje klawiaturę
+	if g.updateFocus() {
+		g.updateAnimation()
+		g.updateSprite()
+		return nil
+	}
+
+	// projekt: Ctrl+S / Ctrl+O, historia: Ctrl+Z / Ctrl+Y (Ctrl+Shift+Z)
+	if ebiten.IsKeyPressed(ebiten.KeyControl) {
+		if inpututil.IsKeyJustPressed(ebiten.KeyZ) {
+			if ebiten.IsKeyPressed(ebiten.KeyShift) {
+				g.redo()
+			} else {
+				g.undo()
+			}
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyY) {
+			g.redo()
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyS) {
+			g.saveProjectDialog()
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyO) {
+			g.loadProjectDialog()
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyD) {
+			if g.spriteMode() {
+				g.duplicateFrame()
+			} else {
+				g.duplicateGlyph()
+			}
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyA) {
+			g.selectAllCode()
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyC) {
+			g.copySelection()
+		}
+	}
+
+	// lista znaków: PgUp / PgDn wybór, Insert wstawia (z Shift przed), Delete usuwa;
+	// w trybie animacji te same klawisze działają na klatkach
+	if g.spriteMode() {
+		g.updateSpriteKeys()
+	} else {
+		if inpututil.IsKeyJustPressed(ebiten.KeyPageUp) && g.activeGlyph > 0 {
+			g.selectGlyph(g.activeGlyph - 1)
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyPageDown) && g.activeGlyph < len(g.glyphs) {
+			g.selectGlyph(g.activeGlyph + 1)
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyInsert) {
+			if ebiten.IsKeyPressed(ebiten.KeyShift) {
+				g.insertGlyphBefore()
+			} else {
+				g.insertGlyphAfter()
+			}
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyDelete) {
+			g.deleteGlyph()
+		}
+	}
+
+	// pipeta
+	if inpututil.IsKeyJustPressed(ebiten.KeyI) {
+		g.eyedropper = !g.eyedropper
+	}
+
+	// narzędzia rysowania (bez Ctrl - Ctrl+O to odczyt projektu)
+	if !ebiten.IsKeyPressed(ebiten.KeyControl) {
+		for t, k := range toolKeys {
+			if inpututil.IsKeyJustPressed(k) {
+				g.tool = t
+			}
+		}
+	}
+
+	// przesuwanie / obroty / lustra / negatyw
+	g.updateTransformKeys()
+
 	if ebiten.IsKeyPressed(ebiten.KeyM) {
 		g.mode = (g.mode + 1) % 4
 		time.Sleep(140 * time.Millisecond)
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyC) {
+	// C czyści siatkę; Ctrl sprawdzany w chwili wciśnięcia (Ctrl+C to kopiowanie)
+	if inpututil.IsKeyJustPressed(ebiten.KeyC) && !ebiten.IsKeyPressed(ebiten.KeyControl) {
+		g.pushUndo()
 		g.clear()
-		time.Sleep(120 * time.Millisecond)
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyE) {
 		err := g.exportC()
@@ -240,25 +544,16 @@ func (g *Game) Update() error {
 		time.Sleep(200 * time.Millisecond) // debounce
 	}
 
-	if g.animRunning {
-		g.animX += float64(g.animDir) * g.animSpeed * 40.0
-		if g.animDir < 0 && g.animX < -float64(GridW*CellSize) {
-			g.animX = float64(GridW * CellSize)
-		} else if g.animDir > 0 && g.animX > float64(GridW*CellSize) {
-			g.animX = -float64(GridW * CellSize)
-		}
-
-	}
+	g.updateAnimation()
+	g.updateSprite()
 
 	return nil
 }
 
+// clear czyści siatkę, także komórki ukryte poza nią (glyphsize.go) -
+// nowy znak zaczyna od zera
 func (g *Game) clear() {
-	for y := 0; y < GridH; y++ {
-		for x := 0; x < GridW; x++ {
-			g.cells[y][x] = 0
-		}
-	}
+	g.cells = [MaxGridH][MaxGridW]int{}
 }
 
 // obsługa slidera prędkości animacji
@@ -274,6 +569,11 @@ func (g *Game) handleSlider(x, y int) bool {
 // obsługa kliknięć: siatka i przyciski
 func (g *Game) handleLeftClick(x, y int) {
 
+	// panel boczny (zakładki)
+	if g.handleSideClick(x, y, 1) {
+		return
+	}
+
 	// NOWY SUWAK: kliknięcie na suwak koloru
 	if x >= g.colorSliderX && x <= g.colorSliderX+g.colorSliderW &&
 		y >= g.colorSliderY && y <= g.colorSliderY+g.colorSliderH {
@@ -281,16 +581,21 @@ func (g *Game) handleLeftClick(x, y int) {
 		return
 	}
 
+	// pasek narzędzi
+	if clickButtons(g.toolButtons(), x, y) {
+		return
+	}
+
 	// --- przycisk pod gridem: zapisz znak ---
 	btnX := 12
-	btnY := GridH*CellSize + 12
-	btnW := GridW*CellSize - 24
+	btnY := EditorSize + 12
+	btnW := EditorSize - 24
 	btnH := 32
 
 	if x >= btnX && x <= btnX+btnW &&
 		y >= btnY && y <= btnY+btnH {
 
-		g.addGlyph()
+		g.saveGlyph()
 		return
 	}
 
@@ -308,79 +613,52 @@ func (g *Game) handleLeftClick(x, y int) {
 	// Poprzedni znak
 	if x >= btnPrevX && x <= btnPrevX+btnPrevW &&
 		y >= btnPrevY && y <= btnPrevY+btnPrevH {
-		//fmt.Println("Poprzedni klik:", x, y)
 		if g.activeGlyph > 0 {
-			g.activeGlyph--
-			g.glyphIndex = g.activeGlyph
-			g.loadGlyphToGrid(g.activeGlyph)
-
-			// przesuwamy widok, jeśli aktywny znak wychodzi poza 8-znakowy widok
-			if g.activeGlyph < g.glyphViewOfs {
-				g.glyphViewOfs = g.activeGlyph
-			}
+			g.selectGlyph(g.activeGlyph - 1)
 		}
 		return
 	}
 
-	// Następny znak
+	// Następny znak (za ostatnim - nowy, pusty znak)
 	if x >= btnNextX && x <= btnNextX+btnNextW &&
 		y >= btnNextY && y <= btnNextY+btnNextH {
-		//fmt.Println("Następny klik:", x, y)
-		if g.activeGlyph < len(g.glyphs)-1 {
-			g.activeGlyph++
-			g.glyphIndex = g.activeGlyph
-			g.loadGlyphToGrid(g.activeGlyph)
-
-			// przesuwamy widok jeśli aktywny znak wychodzi poza 8-znakowy widok
-			if g.activeGlyph >= g.glyphViewOfs+8 {
-				g.glyphViewOfs = g.activeGlyph - 7
-			}
+		if g.activeGlyph < len(g.glyphs) {
+			g.selectGlyph(g.activeGlyph + 1)
 		}
 		return
 	}
 
+	// przeglądarka znaków: pole "Kod", wybór / przeciąganie miniatur
+	if g.handleBrowserClick(x, y) {
+		return
+	}
+
+	// podgląd kodu: przyciski schowka, zaznaczanie
+	if g.handleCodeViewClick(x, y) {
+		return
+	}
+
+	// pole tekstu przewijanego
+	if g.handleMarqueeClick(x, y) {
+		return
+	}
+
 	// kliknięcia na siatkę
-	if y < GridH*CellSize {
+	if x < GridW*CellSize && y < GridH*CellSize {
 		cx := x / CellSize
 		yi := y / CellSize
 		if cx >= 0 && cx < GridW && yi >= 0 && yi < GridH {
-			switch g.mode {
-			case ModeMono:
-				if g.cells[yi][cx] == 0 {
-					g.cells[yi][cx] = g.monoColor
-				} else {
-					g.cells[yi][cx] = 0
-				}
-			case ModeTwo:
-				n := g.cells[yi][cx]
-				if n == 0 {
-					g.cells[yi][cx] = g.twoA
-				} else if n == g.twoA {
-					g.cells[yi][cx] = g.twoB
-				} else {
-					g.cells[yi][cx] = 0
-				}
-			case ModeRGB:
-				n := g.cells[yi][cx]
-				if n >= 4 {
-					g.cells[yi][cx] = 0
-				} else {
-					g.cells[yi][cx] = n + 1
-				}
-			case ModeWS2812B:
-				if g.cells[yi][cx] == g.monoColor {
-					g.cells[yi][cx] = 0 // wyłącz diodę jeśli ma ten sam kolor
-				} else {
-					g.cells[yi][cx] = g.monoColor // ustaw kolor z suwaka
-				}
+			if g.eyedropper {
+				g.sampleCell(g.cells[yi][cx])
+				return
 			}
-
+			g.beginStroke(cx, yi, ebiten.MouseButtonLeft)
 		}
 		return
 	}
 
 	// panel prawy
-	px := GridW * CellSize
+	px := EditorSize
 	bx := x - px
 	by0 := 12 // początek pierwszego przycisku
 	btnW = 180
@@ -400,6 +678,7 @@ func (g *Game) handleLeftClick(x, y int) {
 
 	// 2. Clear
 	if click(by0) {
+		g.pushUndo()
 		g.clear()
 		return
 	}
@@ -407,7 +686,7 @@ func (g *Game) handleLeftClick(x, y int) {
 
 	// 3. Eksport: tryb
 	if click(by0) {
-		g.exportMode = (g.exportMode + 1) % 3
+		g.exportMode = (g.exportMode + 1) % exportModeCount
 		g.updatePreviewText()
 		return
 	}
@@ -453,7 +732,8 @@ func (g *Game) handleLeftClick(x, y int) {
 	if click(by0) {
 		g.animRunning = !g.animRunning
 		if g.animRunning {
-			g.animX = float64(GridW * CellSize)
+			g.restartMarquee()
+			g.resetEffect()
 		}
 		return
 	}
@@ -466,33 +746,32 @@ func (g *Game) handleLeftClick(x, y int) {
 	}
 	by0 += btnH + pad
 
-	// 10. Podgląd HEX/BIN
+	// 10. Podgląd: eksport czcionki / HEX/BIN siatki
 	if click(by0) {
+		g.previewGrid = !g.previewGrid
+		g.previewHLGlyph = -2 // przewiń do bieżącego znaku
+		g.textScroll = 0
 		g.updatePreviewText()
 		return
 	}
+}
 
-	_, wy := ebiten.Wheel()
-	if wy != 0 {
-		x, y := ebiten.CursorPosition()
-
-		// --- obszar glyph preview ---
-		if g.isInGlyphPreview(x, y) {
-			g.glyphScroll -= int(wy) * 16
-			if g.glyphScroll < 0 {
-				g.glyphScroll = 0
-			}
-		}
+// obsługa prawego przycisku myszy
+func (g *Game) handleRightClick(x, y int) {
+	if g.handleSideClick(x, y, -1) {
+		return
+	}
 
-		// --- obszar tekstowego preview ---
-		if g.isInTextPreview(x, y) {
-			g.textScroll -= int(wy) * 16
-			if g.textScroll < 0 {
-				g.textScroll = 0
-			}
-		}
+	// PPM na wierszu podglądu HEX - edycja bajtów
+	if g.isInTextPreview(x, y) {
+		g.beginHexEdit(g.codePosAt(x, y))
+		return
 	}
 
+	// PPM na siatce - gumka w bieżącym narzędziu
+	if cx, cy, ok := cellAt(x, y); ok && !g.stroke.active {
+		g.beginStroke(cx, cy, ebiten.MouseButtonRight)
+	}
 }
 
 func (g *Game) cellsToSlice() [][]int {
@@ -506,59 +785,98 @@ func (g *Game) cellsToSlice() [][]int {
 	return s
 }
 
-// buildDisplayFrame tworzy pełną ramkę do wyświetlenia na matrycach
+// buildSerialFrame - ramka dla matryc: animacja, przewijany tekst albo
+// edytowany znak z sąsiadami
+func (g *Game) buildSerialFrame() [][]int {
+	if g.spriteStream {
+		return g.buildSpriteFrame()
+	}
+	if g.marqueeHW {
+		return g.buildMarqueeFrame()
+	}
+	return g.buildDisplayFrame()
+}
+
+// buildDisplayFrame tworzy pełną ramkę do wyświetlenia na matrycach:
+// edytowany znak, a za nim zapisane znaki z g.displayGlyphs, po GridW kolumn
+// lub wg szerokości (czcionka proporcjonalna); znaki wyższe niż matryca -
+// górne MatrixSize wierszy
 func (g *Game) buildDisplayFrame() [][]int {
-	frame := make([][]int, 8)
-	for y := 0; y < 8; y++ {
-		frame[y] = make([]int, 32) // 4 matryce po 8 kolumn
+	frame := make([][]int, MatrixSize)
+	for y := range frame {
+		frame[y] = make([]int, 4*MatrixSize) // 4 matryce po 8 kolumn
 	}
 
-	// M0 – aktualnie edytowany znak (kolumny 0–7)
-	for y := 0; y < 8; y++ {
-		for x := 0; x < 8; x++ {
-			frame[y][x] = g.cells[y][x]
-		}
+	strip := g.displayStrip()
+	for y := 0; y < min(GridH, MatrixSize); y++ {
+		copy(frame[y], strip[y])
+	}
+
+	return frame
+}
+
+// buildWS2812Display składa obraz całego wyświetlacza WS2812: znaki
+// GridW x GridH układane od lewego górnego rogu niezależnie od paneli
+// (edytowany znak, potem zapisane z g.displayGlyphs), nadmiar przycięty
+func (g *Game) buildWS2812Display() [][]int {
+	w, h := g.ws2812Layout.Size()
+	frame := make([][]int, h)
+	for y := range frame {
+		frame[y] = make([]int, w)
 	}
 
-	// M1–M3 – zapisane znaki (wykorzystujemy tylko g.displayGlyphs)
-	for i, glyph := range g.displayGlyphs {
-		if i >= 3 {
+	cols, rows := max(w/GridW, 1), max(h/GridH, 1)
+	tiles := append([]Glyph{Glyph(g.cells)}, g.displayGlyphs...)
+	for k, t := range tiles {
+		if k >= cols*rows {
 			break
 		}
-		for y := 0; y < 8; y++ {
-			b := glyph[y]
-			for bit := 0; bit < 8; bit++ {
-				if (b & (1 << (7 - bit))) != 0 {
-					frame[y][(i+1)*8+bit] = 1
-				}
+		ox := (k % cols) * GridW
+		oy := (k / cols) * GridH
+		for y := 0; y < GridH && oy+y < h; y++ {
+			for x := 0; x < GridW && ox+x < w; x++ {
+				frame[oy+y][ox+x] = t[y][x]
 			}
 		}
 	}
-
 	return frame
 }
 
-// buildWS2812Frame buduje ramkę dla matrycy WS2812B w układzie zig-zag 8x8
-func (g *Game) buildWS2812Frame() [][]int {
-	frame := make([][]int, 8)
-	for y := 0; y < 8; y++ {
-		frame[y] = make([]int, 8)
+// buildWS2812Frame buduje ramkę do wysłania: strumień po kalibracji,
+// przeskalowany przez limiter prądu jeśli jest włączony
+func (g *Game) buildWS2812Frame() []byte {
+	out := g.buildWS2812Stream()
+	if g.power.Limit {
+		g.power.LimitStream(out)
 	}
+	return out
+}
 
-	for y := 0; y < 8; y++ {
-		for x := 0; x < 8; x++ {
-			// określenie fizycznego indeksu kolumny w wierszu zig-zag
-			idxX := x
-			if y%2 == 1 { // co drugi wiersz od prawej do lewej
-				idxX = 7 - x
-			}
-
-			// kolor z komórki (0 = wyłączona, >0 = kolor z suwaka)
-			frame[y][idxX] = g.cells[y][x]
+// buildWS2812Stream buduje strumień bajtów dla łańcucha WS2812B
+// w fizycznej kolejności diod wg g.ws2812Layout (bez limitera)
+func (g *Game) buildWS2812Stream() []byte {
+	l := g.ws2812Layout
+	out := make([]byte, l.NumLEDs()*3)
+	for y, row := range g.buildWS2812Display() {
+		for x, v := range row {
+			c := g.ledCalib.Apply(cellColor(v))
+			b := NewWS2812Color(c, 100).Bytes(g.ws2812Order)
+			copy(out[l.Index(x, y)*3:], b[:])
 		}
 	}
+	return out
+}
 
-	return frame
+// glyphMA szacuje prąd pojedynczego znaku na jednym panelu WS2812
+func (g *Game) glyphMA(gl Glyph) float64 {
+	calib := g.ledCalib
+	stream := ws2812Stream(gl, ExportOptions{
+		WS2812Layout:     g.ws2812Layout,
+		WS2812Order:      g.ws2812Order,
+		WS2812Brightness: 100,
+		WS2812Calib:      &calib,
+	})
+	return g.power.StreamMA(stream)
 }
 
 // loadGlyphToGrid >> wczytuje znak do siatki
@@ -566,17 +884,7 @@ func (g *Game) loadGlyphToGrid(idx int) {
 	if idx < 0 || idx >= len(g.glyphs) {
 		return
 	}
-	glyph := g.glyphs[idx]
-	for y := 0; y < GridH; y++ {
-		row := glyph[y]
-		for x := 0; x < GridW; x++ {
-			if (row & (1 << (7 - x))) != 0 {
-				g.cells[y][x] = 1
-			} else {
-				g.cells[y][x] = 0
-			}
-		}
-	}
+	g.cells = g.glyphs[idx]
 
 	// odśwież wyświetlane glyphy w matrycach M1–M3
 	g.updateDisplayGlyphs()
@@ -584,11 +892,13 @@ func (g *Game) loadGlyphToGrid(idx int) {
 
 // aktualizacja wyświetlanego znaku
 func (g *Game) updateDisplayGlyphs() {
-	g.displayGlyphs = [][]byte{}
+	g.displayGlyphs = []Glyph{}
+	g.displayIdx = []int{}
 	for i := 0; i < 3; i++ {
 		idx := g.activeGlyph + i - 2 // M1 = poprzedni, M2 = kolejny...
 		if idx >= 0 && idx < len(g.glyphs) && idx != g.activeGlyph {
 			g.displayGlyphs = append(g.displayGlyphs, g.glyphs[idx])
+			g.displayIdx = append(g.displayIdx, idx)
 		}
 	}
 }
@@ -10,76 +10,302 @@ package main
 
 import "fmt"
 
+// Glyph - zapisany znak; przechowuje wartości komórek (indeksy palety)
+// tak jak Game.cells, więc eksport kolorowy ma pełne dane
+type Glyph [MaxGridH][MaxGridW]int
+
+// Bits1 zwraca znak jako 1-bit: rowBytes() bajtów na wiersz,
+// bit 7 pierwszego bajtu = lewa kolumna
+func (gl Glyph) Bits1() []byte {
+	n := rowBytes()
+	out := make([]byte, 0, GridH*n)
+	for y := 0; y < GridH; y++ {
+		row := gl.Row1(y)
+		for i := n - 1; i >= 0; i-- {
+			out = append(out, byte(row>>uint(8*i)))
+		}
+	}
+	return out
+}
+
 /*
 func (g *Game) addGlyph() {
-	glyph := g.currentGlyph1Bit()
+	glyph := Glyph(g.cells)
 	g.glyphs = append(g.glyphs, glyph)
 	g.glyphIndex = len(g.glyphs)
 	g.clear()
-	g.updateFontPreview()
+	g.updatePreviewText()
 }
 */
 
-// addGlyph dodaje aktualny znak do listy glyphów, przesuwa wyświetlane znaki
-// i wyczyści edytor
-func (g *Game) addGlyph() {
-	glyph := g.currentGlyph1Bit()
+// isNewGlyph - siatka zawiera nowy, jeszcze nie zapisany znak
+// (activeGlyph == len(g.glyphs))
+func (g *Game) isNewGlyph() bool {
+	return g.activeGlyph < 0 || g.activeGlyph >= len(g.glyphs)
+}
 
-	// dodaj znak do pełnej listy
-	g.glyphs = append(g.glyphs, glyph)
+// scrollToActive przewija przeglądarkę znaków tak, aby aktywny znak był widoczny
+func (g *Game) scrollToActive() {
+	g.scrollToGlyph(g.activeGlyph)
+}
 
-	// ustaw aktywny znak (ostatnio zapisany)
-	g.activeGlyph = len(g.glyphs) - 1
+// gridEmpty - siatka (GridW x GridH) nie ma zapalonych pikseli
+func (g *Game) gridEmpty() bool {
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			if g.cells[y][x] != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
 
-	// przesuwamy okno podglądu (max 8 znaków)
-	if g.activeGlyph >= g.glyphViewOfs+8 {
-		g.glyphViewOfs = g.activeGlyph - 7
+// keepGrid zapisuje siatkę w aktywnym znaku przed przejściem do innego
+// (bez kroku cofania - zmiany są w historii siatki tego znaku)
+func (g *Game) keepGrid() {
+	if !g.isNewGlyph() {
+		g.glyphs[g.activeGlyph] = Glyph(g.cells)
 	}
+}
 
-	// aktualizujemy wyświetlane znaki (dla matryc M1–M3)
-	g.displayGlyphs = [][]byte{}
-	for i := 0; i < 3; i++ {
-		idx := g.activeGlyph + i - 2 // M1 = poprzedni, M2 = kolejny...
-		if idx >= 0 && idx < len(g.glyphs) && idx != g.activeGlyph {
-			g.displayGlyphs = append(g.displayGlyphs, g.glyphs[idx])
-		}
+// commitGrid - jak keepGrid, a narysowany nowy znak dopisuje na koniec
+// listy (krok cofania listy); zwraca true, gdy dopisał znak
+func (g *Game) commitGrid() bool {
+	if !g.isNewGlyph() {
+		g.keepGrid()
+		return false
+	}
+	if g.gridEmpty() {
+		return false
 	}
+	g.addGlyph()
+	g.lastExport = fmt.Sprintf("Zapisano nowy znak %d", len(g.glyphs)-1)
+	return true
+}
+
+// selectGlyph wybiera znak idx; idx == len(g.glyphs) to nowy, pusty znak;
+// zmiany siatki nie giną - trafiają do opuszczanego znaku (commitGrid)
+func (g *Game) selectGlyph(idx int) {
+	if g.fontListLocked() {
+		return
+	}
+	toNew := idx >= len(g.glyphs)
+	if g.commitGrid() && toNew {
+		idx = len(g.glyphs)
+	}
+	g.showGlyph(idx)
+}
 
+// showGlyph wczytuje znak idx do siatki (bez zapisu bieżącej siatki -
+// dla operacji, które same zmieniły listę)
+func (g *Game) showGlyph(idx int) {
+	idx = clampInt(idx, 0, len(g.glyphs))
+	g.activeGlyph = idx
+	g.glyphIndex = idx
+	if g.isNewGlyph() {
+		g.clear()
+		g.updateDisplayGlyphs()
+	} else {
+		g.loadGlyphToGrid(idx)
+	}
+	g.scrollToActive()
+	g.updatePreviewText()
+}
+
+// saveGlyph - "Zapisz znak": zapisuje siatkę z powrotem do aktywnego znaku,
+// a nowy znak dopisuje na koniec listy
+func (g *Game) saveGlyph() {
+	if g.fontListLocked() {
+		return
+	}
+	if g.isNewGlyph() {
+		g.addGlyph()
+		return
+	}
+	g.pushListUndo()
+	g.glyphs[g.activeGlyph] = Glyph(g.cells)
+	g.updateDisplayGlyphs()
+	g.updatePreviewText()
+	g.lastExport = fmt.Sprintf("Zapisano znak %d", g.activeGlyph)
+}
+
+// addGlyph dodaje aktualny znak na koniec listy glyphów, przesuwa wyświetlane
+// znaki i czyści edytor na kolejny nowy znak
+func (g *Game) addGlyph() {
+	glyph := Glyph(g.cells)
+
+	g.pushListUndo()
+
+	// dodaj znak do pełnej listy (razem z pustą historią siatki)
+	g.glyphAdv = append(g.advSlice(), 0)
+	g.glyphs = append(g.glyphs, glyph)
+	g.glyphHist = append(g.glyphHist, nil)
+
+	// aktywny jest kolejny nowy znak za ostatnio zapisanym
+	g.activeGlyph = len(g.glyphs)
 	g.glyphIndex = g.activeGlyph
+	g.scrollToActive()
+
+	// aktualizujemy wyświetlane znaki (dla matryc M1–M3)
+	g.updateDisplayGlyphs()
 
 	// wyczyść edytor
 	g.clear()
 
 	// odśwież preview fontu
-	g.updateFontPreview()
+	g.updatePreviewText()
 }
 
-func (g *Game) currentGlyph1Bit() []byte {
-	out := make([]byte, 8)
-	for y := 0; y < GridH; y++ {
-		var row byte
-		for x := 0; x < GridW; x++ {
-			if g.cells[y][x] != 0 {
-				row |= 1 << (7 - x)
-			}
-		}
-		out[y] = row
+// insertGlyph wstawia pusty znak na pozycję at i wybiera go
+func (g *Game) insertGlyph(at int) {
+	if g.fontListLocked() {
+		return
 	}
-	return out
+	at = clampInt(at, 0, len(g.glyphs))
+	g.pushListUndo()
+	adv := g.advSlice()
+	g.glyphAdv = append(adv[:at:at], append([]int{0}, adv[at:]...)...)
+	g.glyphs = append(g.glyphs[:at:at], append([]Glyph{{}}, g.glyphs[at:]...)...)
+	g.glyphHist = append(g.glyphHist[:at:at], append([]*undoStack{nil}, g.glyphHist[at:]...)...)
+	g.showGlyph(at)
 }
 
-func (g *Game) updateFontPreview() {
-	s := fmt.Sprintf("char font8x8[%d][8] = {\n", len(g.glyphs))
-	for i, glyph := range g.glyphs {
-		s += "  { "
-		for j, b := range glyph {
-			s += fmt.Sprintf("0x%02X", b)
-			if j < 7 {
-				s += ", "
-			}
-		}
-		s += fmt.Sprintf(" }, // znak %d\n", i)
+// insertGlyphBefore / insertGlyphAfter - wstawienie względem aktywnego znaku
+// (najpierw zapis siatki, pozycja liczona po nim)
+func (g *Game) insertGlyphBefore() {
+	if !g.fontListLocked() {
+		g.commitGrid()
+	}
+	g.insertGlyph(g.activeGlyph)
+}
+
+func (g *Game) insertGlyphAfter() {
+	if !g.fontListLocked() {
+		g.commitGrid()
+	}
+	g.insertGlyph(g.activeGlyph + 1)
+}
+
+// duplicateGlyph wstawia kopię siatki za aktywnym znakiem i wybiera kopię
+func (g *Game) duplicateGlyph() {
+	if g.fontListLocked() {
+		return
+	}
+	g.keepGrid()
+	gl := Glyph(g.cells)
+	at := clampInt(g.activeGlyph+1, 0, len(g.glyphs))
+	g.pushListUndo()
+	adv := g.advSlice()
+	g.glyphAdv = append(adv[:at:at], append([]int{g.advanceOverride(g.activeGlyph)}, adv[at:]...)...)
+	g.glyphs = append(g.glyphs[:at:at], append([]Glyph{gl}, g.glyphs[at:]...)...)
+	g.glyphHist = append(g.glyphHist[:at:at], append([]*undoStack{nil}, g.glyphHist[at:]...)...)
+	g.showGlyph(at)
+}
+
+// deleteGlyph usuwa aktywny znak; aktywny staje się następny
+func (g *Game) deleteGlyph() {
+	if g.isNewGlyph() || g.fontListLocked() {
+		return
+	}
+	at := g.activeGlyph
+	g.pushListUndo()
+	adv := g.advSlice()
+	g.glyphAdv = append(adv[:at:at], adv[at+1:]...)
+	g.glyphs = append(g.glyphs[:at:at], g.glyphs[at+1:]...)
+	g.glyphHist = append(g.glyphHist[:at:at], g.glyphHist[at+1:]...)
+	g.showGlyph(at)
+}
+
+// moveGlyph przenosi znak from na pozycję slotu to (przeciąganie miniatur);
+// to liczone jest w liście przed usunięciem znaku
+func (g *Game) moveGlyph(from, to int) {
+	if g.fontListLocked() {
+		return
+	}
+	if from < 0 || from >= len(g.glyphs) {
+		return
+	}
+	if to > from {
+		to-- // po wyjęciu znaku pozycje za nim przesuwają się o 1
 	}
-	s += "};\n"
-	g.previewText = s
+	to = clampInt(to, 0, len(g.glyphs)-1)
+	if to == from {
+		return
+	}
+
+	g.pushListUndo()
+	all := g.advSlice()
+	gl, h, a := g.glyphs[from], g.glyphHist[from], all[from]
+	glyphs := append(g.glyphs[:from:from], g.glyphs[from+1:]...)
+	hist := append(g.glyphHist[:from:from], g.glyphHist[from+1:]...)
+	adv := append(all[:from:from], all[from+1:]...)
+	g.glyphs = append(glyphs[:to:to], append([]Glyph{gl}, glyphs[to:]...)...)
+	g.glyphHist = append(hist[:to:to], append([]*undoStack{h}, hist[to:]...)...)
+	g.glyphAdv = append(adv[:to:to], append([]int{a}, adv[to:]...)...)
+
+	// aktywny znak idzie za swoją zawartością
+	switch {
+	case g.activeGlyph == from:
+		g.activeGlyph = to
+	case g.isNewGlyph():
+		// nowy znak zostaje za końcem listy
+	case from < g.activeGlyph && to >= g.activeGlyph:
+		g.activeGlyph--
+	case from > g.activeGlyph && to <= g.activeGlyph:
+		g.activeGlyph++
+	}
+	g.glyphIndex = g.activeGlyph
+	g.scrollToActive()
+	g.updateDisplayGlyphs()
+	g.updatePreviewText()
+}
+
+// beginThumbDrag - LPM na miniaturze: wybór lub początek przeciągania
+func (g *Game) beginThumbDrag(x, y int) bool {
+	idx, _ := g.thumbAt(x, y)
+	if idx < 0 {
+		return false
+	}
+	if idx == len(g.glyphs) {
+		g.selectGlyph(idx) // pole "+" - nowy znak
+		return true
+	}
+	g.thumbDrag = idx
+	g.thumbDrop = idx
+	return true
+}
+
+// dragThumb - przeciąganie miniatury: zapamiętuje miejsce upuszczenia
+func (g *Game) dragThumb(x, y int) {
+	if _, slot := g.thumbAt(x, y); slot >= 0 {
+		g.thumbDrop = slot
+	}
+}
+
+// endThumbDrag - puszczenie LPM: przeniesienie lub (bez ruchu) wybór znaku
+func (g *Game) endThumbDrag() {
+	from, to := g.thumbDrag, g.thumbDrop
+	g.thumbDrag = -1
+	if to == from || to == from+1 {
+		g.selectGlyph(from)
+		return
+	}
+	g.moveGlyph(from, to)
+}
+
+// glyphListButtons - przyciski zarządzania listą znaków (zakładka "Znak")
+func (g *Game) glyphListButtons(x0, y0 int) []uiButton {
+	bw := (OptionsW - 16) / 3
+	return []uiButton{
+		{x0 + 8, y0, bw - 4, 24, "Wstaw przed", g.insertGlyphBefore},
+		{x0 + 8 + bw, y0, bw - 4, 24, "Wstaw za", g.insertGlyphAfter},
+		{x0 + 8 + 2*bw, y0, bw - 4, 24, "Nowy", func() { g.selectGlyph(len(g.glyphs)) }},
+		{x0 + 8, y0 + 30, bw - 4, 24, "Duplikuj", g.duplicateGlyph},
+		{x0 + 8 + bw, y0 + 30, bw - 4, 24, "Usuń", g.deleteGlyph},
+	}
+}
+
+func (g *Game) currentGlyph1Bit() []byte {
+	return Glyph(g.cells).Bits1()
 }
@@ -0,0 +1,135 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: glyphsize.go
+
+Rozmiar znaku jako ustawienie projektu (zakładka "Projekt"):
+- gotowe rozmiary 5x7, 6x8, 8x8, 8x16, 16x16 albo dowolny do 16x16
+  (opcje "Szerokość znaku" / "Wysokość znaku")
+- siatka edytora zawsze zajmuje ten sam obszar, komórki się skalują
+- wiersze szersze niż 8 pikseli: w eksporcie 1-bit jako kolejne bajty
+  (bit 7 pierwszego bajtu = lewa kolumna) albo jako uint16;
+  2-bit: uint8 / uint16 / uint32 zależnie od szerokości
+- bity wiersza są wyrównane do lewej: lewa kolumna = najstarszy bit
+
+Zmiana rozmiaru niczego nie kasuje: znaki, siatka i klatki animacji
+trzymają pełne MaxGridW x MaxGridH, a GridW x GridH decyduje tylko o tym,
+co widać, co idzie do eksportu i do pliku projektu - po powrocie do
+większego rozmiaru ukryte kolumny / wiersze wracają, historia cofania
+zostaje.
+
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// bieżący rozmiar znaku i komórki siatki edytora (ustawiane przez setGlyphSize)
+var (
+	GridW    = 8
+	GridH    = 8
+	CellSize = EditorSize / 8
+)
+
+// glyphSizePresets - gotowe rozmiary znaku (szerokość, wysokość)
+var glyphSizePresets = [][2]int{{5, 7}, {6, 8}, {8, 8}, {8, 16}, {16, 16}}
+
+// najmniejszy rozmiar znaku
+const MinGridSize = 3
+
+// setGlyphSize zmienia rozmiar znaku (dane poza siatką zostają ukryte)
+func (g *Game) setGlyphSize(w, h int) {
+	w = clampInt(w, MinGridSize, MaxGridW)
+	h = clampInt(h, MinGridSize, MaxGridH)
+	if w == GridW && h == GridH {
+		return
+	}
+	GridW, GridH = w, h
+	CellSize = EditorSize / max(w, h)
+
+	g.clampMetrics()
+	g.batchPad = clampInt(g.batchPad, 0, GridW-1)
+	if matrixSerial != nil {
+		matrixSerial.lastWS2812 = nil // długość ramki WS2812 się zmienia
+	}
+	g.setGlyphScroll(g.glyphScroll)
+	g.scrollToActive()
+	g.updateDisplayGlyphs()
+	g.updatePreviewText()
+	g.lastExport = fmt.Sprintf("Rozmiar znaku: %dx%d", GridW, GridH)
+}
+
+// cropGlyph zeruje komórki poza bieżącą siatką GridW x GridH
+func cropGlyph(gl Glyph) Glyph {
+	var out Glyph
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			out[y][x] = gl[y][x]
+		}
+	}
+	return out
+}
+
+// MarshalJSON zapisuje tylko bieżącą siatkę GridW x GridH
+// (odczyt krótszych tablic uzupełnia resztę zerami)
+func (gl Glyph) MarshalJSON() ([]byte, error) {
+	rows := make([][]int, GridH)
+	for y := range rows {
+		rows[y] = gl[y][:GridW]
+	}
+	return json.Marshal(rows)
+}
+
+// rowBytes - liczba bajtów wiersza 1-bit
+func rowBytes() int {
+	return (GridW + 7) / 8
+}
+
+// containerBits - najmniejszy typ C (8/16/32 bit) mieszczący bits bitów
+func containerBits(bits int) int {
+	switch {
+	case bits <= 8:
+		return 8
+	case bits <= 16:
+		return 16
+	}
+	return 32
+}
+
+// ctypeBits - typ C dla liczby o szerokości bits (8/16/32)
+func ctypeBits(bits int) string {
+	return fmt.Sprintf("uint%d_t", bits)
+}
+
+// rowBits1 / rowBits2 - szerokość wiersza w eksporcie 1-bit i 2-bit
+func rowBits1() int { return rowBytes() * 8 }
+func rowBits2() int { return containerBits(2 * GridW) }
+
+// Row1 - wiersz y jako liczba 1-bit (rowBits1 bitów, lewa kolumna = najstarszy bit)
+func (gl Glyph) Row1(y int) uint32 {
+	var row uint32
+	for x := 0; x < GridW; x++ {
+		if gl[y][x] != 0 {
+			row |= 1 << uint(rowBits1()-1-x)
+		}
+	}
+	return row
+}
+
+// Row2 - wiersz y jako liczba 2-bit (2 bity na piksel = indeks palety 0..3)
+func (gl Glyph) Row2(y int) uint32 {
+	var row uint32
+	for x := 0; x < GridW; x++ {
+		row |= uint32(paletteIndex(gl[y][x])%4) << uint(rowBits2()-2-2*x)
+	}
+	return row
+}
+
+// hexDigits - zapis liczby o szerokości bits jako 0x.. z pełną liczbą cyfr
+func hexDigits(v uint32, bits int) string {
+	return fmt.Sprintf("0x%0*X", bits/4, v)
+}
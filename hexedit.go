@@ -0,0 +1,198 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: hexedit.go
+
+Edycja bajtów w podglądzie HEX/BIN - zmiana wartości od razu zmienia siatkę:
+- 1-bit:  wiersz "ROW n" jako bajt (0x3C, 0b00111100, 60), szersze znaki uint16
+- 2-bit:  wiersz "ROW n" jako uint16 / uint32, 2 bity na piksel = indeks palety 0..3
+- RGB565: pojedynczy piksel "x,y" jako uint16
+
+Działa w podglądzie "HEX/BIN" (przycisk podglądu po prawej). Podwójne
+kliknięcie LPM lub PPM na wierszu podglądu otwiera pole edycji,
+Enter zatwierdza, Esc anuluje. Błędna wartość jest pokazana pod polem.
+
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// długość wpisu piksela w podglądzie RGB: "00,00: 0xF800  "
+const rgbPreviewCellW = 15
+
+// czas podwójnego kliknięcia
+const doubleClickTime = 400 * time.Millisecond
+
+// hexEditTarget - edytowany wiersz / piksel podglądu
+type hexEditTarget struct {
+	line int // wiersz podglądu (pozycja pola)
+	row  int // wiersz siatki
+	col  int // kolumna siatki (RGB565) lub -1 (cały wiersz)
+}
+
+// hexEditable - czy podgląd HEX/BIN w bieżącym trybie eksportu można edytować
+func (g *Game) hexEditable() bool {
+	if !g.previewGrid {
+		return false
+	}
+	switch g.exportMode {
+	case Export1Bit, Export2Bit, ExportRGB:
+		return true
+	}
+	return false
+}
+
+// hexTargetAt zwraca edytowalny element podglądu w pozycji p
+func (g *Game) hexTargetAt(p textPos) (hexEditTarget, bool) {
+	lines := g.codeLines()
+	if !g.hexEditable() || p.line >= len(lines) {
+		return hexEditTarget{}, false
+	}
+	ln := string(lines[p.line])
+	t := hexEditTarget{line: p.line, col: -1}
+
+	if g.exportMode == ExportRGB {
+		if _, err := fmt.Sscanf(ln, "00,%d:", &t.row); err != nil {
+			return t, false
+		}
+		t.col = clampInt(p.col/rgbPreviewCellW, 0, GridW-1)
+	} else if _, err := fmt.Sscanf(ln, "ROW %d:", &t.row); err != nil {
+		return t, false
+	}
+	return t, t.row >= 0 && t.row < GridH
+}
+
+// hexValue - bieżąca wartość edytowanego elementu jako tekst
+func (g *Game) hexValue(t hexEditTarget) string {
+	switch g.exportMode {
+	case Export1Bit:
+		return hexDigits(Glyph(g.cells).Row1(t.row), rowBits1())
+	case Export2Bit:
+		return hexDigits(Glyph(g.cells).Row2(t.row), rowBits2())
+	}
+	return fmt.Sprintf("0x%04X", rgb565(cellColor(g.cells[t.row][t.col])))
+}
+
+// parseHexValue sprawdza tekst pola; komunikaty bez polskich znaków (DebugPrint)
+func (g *Game) parseHexValue(s string) (uint64, error) {
+	bits := 16
+	switch g.exportMode {
+	case Export1Bit:
+		bits = rowBits1()
+	case Export2Bit:
+		bits = rowBits2()
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("pusta wartosc")
+	}
+	v, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("zla liczba: %s (np. 0x3C)", s)
+	}
+	if v >= 1<<bits {
+		return 0, fmt.Errorf("poza zakresem 0..0x%X", uint64(1)<<bits-1)
+	}
+	return v, nil
+}
+
+// beginHexEdit otwiera pole edycji dla pozycji p podglądu
+func (g *Game) beginHexEdit(p textPos) bool {
+	t, ok := g.hexTargetAt(p)
+	if !ok {
+		return false
+	}
+	g.hexTarget = t
+	g.hexEdit.text = g.hexValue(t)
+	g.hexEdit.err = ""
+	g.focusInput(&g.hexEdit)
+	return true
+}
+
+// codeDoubleClick - LPM w podglądzie: drugie kliknięcie w tym samym wierszu
+// w krótkim czasie otwiera edycję
+func (g *Game) codeDoubleClick(p textPos) bool {
+	now := time.Now()
+	double := p.line == g.codeClickLine && now.Sub(g.codeClickTime) < doubleClickTime
+	g.codeClickLine, g.codeClickTime = p.line, now
+	return double && g.beginHexEdit(p)
+}
+
+// applyHexEdit - Enter w polu edycji: nowa wartość trafia do siatki
+func (g *Game) applyHexEdit(s string) {
+	v, err := g.parseHexValue(s)
+	if err != nil {
+		return
+	}
+	t := g.hexTarget
+	g.pushUndo()
+
+	switch g.exportMode {
+	case Export1Bit:
+		for x := 0; x < GridW; x++ {
+			on := v&(1<<uint(rowBits1()-1-x)) != 0
+			switch {
+			case !on:
+				g.cells[t.row][x] = 0
+			case g.cells[t.row][x] == 0:
+				g.cells[t.row][x] = g.paintValue()
+			}
+		}
+	case Export2Bit:
+		for x := 0; x < GridW; x++ {
+			idx := int(v>>uint(rowBits2()-2-2*x)) & 3
+			if paletteIndex(g.cells[t.row][x])%4 != idx {
+				g.cells[t.row][x] = idx
+			}
+		}
+	case ExportRGB:
+		cur := g.cells[t.row][t.col]
+		if uint64(rgb565(cellColor(cur))) != v {
+			g.cells[t.row][t.col] = colorCell(rgb565ToRGBA(uint16(v)))
+		}
+	}
+	g.updatePreviewText()
+}
+
+// colorCell - wartość komórki dla koloru: indeks palety, jeśli kolor w niej
+// jest, w przeciwnym razie kolor 24-bit
+func colorCell(c color.RGBA) int {
+	if c.R == 0 && c.G == 0 && c.B == 0 {
+		return 0
+	}
+	for i, p := range palette {
+		if p.R == c.R && p.G == c.G && p.B == c.B {
+			return i
+		}
+	}
+	return trueColor(c)
+}
+
+// drawHexEdit rysuje pole edycji na wierszu podglądu
+func (g *Game) drawHexEdit(screen *ebiten.Image) {
+	if g.focus != &g.hexEdit {
+		return
+	}
+	tx, ty, cols, rows := g.codeTextArea()
+	r := g.hexTarget.line - g.textScroll
+	if r < 0 || r >= rows {
+		return
+	}
+	x := tx
+	if g.hexTarget.col >= 0 {
+		x += max(0, g.hexTarget.col*rgbPreviewCellW-g.textScrollX) * codeCharW
+	}
+	w := min(110, tx+cols*codeCharW-x)
+	g.hexEdit.draw(screen, x, ty+r*codeLineH-4, w, 20, true)
+}
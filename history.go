@@ -0,0 +1,268 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: history.go
+
+Historia cofania (Ctrl+Z) i ponawiania (Ctrl+Y / Ctrl+Shift+Z).
+
+- edycje siatki mają osobną historię dla każdego znaku (g.glyphHist,
+  równoległa do g.glyphs) - po przejściu << / >> Ctrl+Z cofa zmiany
+  właśnie tego znaku; nowy, jeszcze nie zapisany znak ma g.newHist
+- operacje na liście znaków (dodanie, zamiana, usunięcie ...) mają
+  wspólną historię g.listHist
+- numer kolejny (seq) decyduje, która zmiana była ostatnia
+
+*/
+
+package main
+
+// domyślna głębokość historii
+const DefaultUndoDepth = 50
+
+// limit historii listy znaków: łączna liczba znaków we wszystkich
+// migawkach (każda migawka to kopia całej listy) - ok. 40 MB
+const MaxListUndoGlyphs = 20000
+
+// undoEntry - stan sprzed zmiany
+type undoEntry struct {
+	seq   int
+	cells Glyph
+	list  *listState // operacja na liście znaków (nil = edycja siatki)
+}
+
+// listState - stan listy znaków
+type listState struct {
+	glyphs  []Glyph
+	hist    []*undoStack
+	adv     []int
+	active  int
+	viewOfs int
+	index   int
+}
+
+// undoStack - stosy cofania i ponawiania
+type undoStack struct {
+	undo []undoEntry
+	redo []undoEntry
+}
+
+// push dokłada wpis cofania, najstarsze wpisy ponad depth odpadają
+func (s *undoStack) push(e undoEntry, depth int) {
+	s.undo = append(s.undo, e)
+	s.trim(depth)
+}
+
+// trim obcina stosy do depth wpisów (najstarsze cofnięcia, najdalsze ponowienia)
+func (s *undoStack) trim(depth int) {
+	if depth <= 0 {
+		return
+	}
+	if len(s.undo) > depth {
+		s.undo = append([]undoEntry(nil), s.undo[len(s.undo)-depth:]...)
+	}
+	if len(s.redo) > depth {
+		s.redo = append([]undoEntry(nil), s.redo[len(s.redo)-depth:]...)
+	}
+}
+
+// trimListGlyphs usuwa najstarsze migawki listy, dopóki łącznie mają
+// więcej niż limit znaków (ostatnia migawka zostaje zawsze)
+func (s *undoStack) trimListGlyphs(limit int) {
+	n := 0
+	for i := len(s.undo) - 1; i >= 0; i-- {
+		if l := s.undo[i].list; l != nil {
+			n += len(l.glyphs)
+		}
+		if n > limit && i < len(s.undo)-1 {
+			s.undo = append([]undoEntry(nil), s.undo[i+1:]...)
+			return
+		}
+	}
+}
+
+func top(entries []undoEntry) int {
+	if len(entries) == 0 {
+		return -1
+	}
+	return entries[len(entries)-1].seq
+}
+
+func pop(entries *[]undoEntry) undoEntry {
+	e := (*entries)[len(*entries)-1]
+	*entries = (*entries)[:len(*entries)-1]
+	return e
+}
+
+// cellHist zwraca historię siatki aktywnego znaku
+func (g *Game) cellHist() *undoStack {
+	if g.spriteMode() {
+		return g.frameHist() // sprite.go
+	}
+	if g.activeGlyph < 0 || g.activeGlyph >= len(g.glyphHist) {
+		return &g.newHist
+	}
+	if g.glyphHist[g.activeGlyph] == nil {
+		g.glyphHist[g.activeGlyph] = &undoStack{}
+	}
+	return g.glyphHist[g.activeGlyph]
+}
+
+func (g *Game) listSnapshot() *listState {
+	return &listState{
+		glyphs:  append([]Glyph(nil), g.glyphs...),
+		hist:    append([]*undoStack(nil), g.glyphHist...),
+		adv:     append([]int(nil), g.glyphAdv...),
+		active:  g.activeGlyph,
+		viewOfs: g.glyphViewOfs,
+		index:   g.glyphIndex,
+	}
+}
+
+func (g *Game) restoreList(s *listState) {
+	g.glyphs = s.glyphs
+	g.glyphHist = s.hist
+	g.glyphAdv = s.adv
+	g.activeGlyph = s.active
+	g.glyphIndex = s.index
+	g.setGlyphScroll(s.viewOfs / g.browserCols() * browserPitchY())
+	g.updateDisplayGlyphs()
+}
+
+// pushUndo zapamiętuje siatkę przed jej zmianą
+func (g *Game) pushUndo() {
+	g.undoSeq++
+	h := g.cellHist()
+	h.push(undoEntry{seq: g.undoSeq, cells: Glyph(g.cells)}, g.undoDepth)
+	h.redo = nil
+	g.listHist.redo = nil
+}
+
+// pushListUndo zapamiętuje listę znaków i siatkę przed operacją na liście
+func (g *Game) pushListUndo() {
+	g.undoSeq++
+	g.listHist.push(undoEntry{seq: g.undoSeq, cells: Glyph(g.cells), list: g.listSnapshot()}, g.undoDepth)
+	g.listHist.trimListGlyphs(MaxListUndoGlyphs)
+	g.listHist.redo = nil
+	g.cellHist().redo = nil
+}
+
+// resetHistory czyści całą historię (np. po wczytaniu projektu)
+func (g *Game) resetHistory() {
+	g.glyphHist = make([]*undoStack, len(g.glyphs))
+	g.newHist = undoStack{}
+	g.listHist = undoStack{}
+	for i := range g.sprite.Frames {
+		g.sprite.Frames[i].hist = nil
+	}
+}
+
+// eachStack wywołuje fn dla każdego stosu historii: znaków, nowego znaku,
+// listy, klatek i stosów zapamiętanych w migawkach listy (każdy raz)
+func (g *Game) eachStack(fn func(s *undoStack)) {
+	seen := map[*undoStack]bool{}
+	var stack func(s *undoStack)
+	entries := func(es []undoEntry) {
+		for _, e := range es {
+			if e.list != nil {
+				for _, h := range e.list.hist {
+					stack(h)
+				}
+			}
+		}
+	}
+	stack = func(s *undoStack) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		entries(s.undo)
+		entries(s.redo)
+		fn(s)
+	}
+
+	for _, h := range g.glyphHist {
+		stack(h)
+	}
+	stack(&g.newHist)
+	stack(&g.listHist)
+	for i := range g.sprite.Frames {
+		stack(g.sprite.Frames[i].hist)
+	}
+}
+
+// remapHistory stosuje f do każdego znaku zapamiętanego w historii
+// (siatki, migawki listy znaków, klatki) - np. po zmianie indeksów palety
+func (g *Game) remapHistory(f func(gl *Glyph)) {
+	entries := func(es []undoEntry) {
+		for i := range es {
+			f(&es[i].cells)
+			if l := es[i].list; l != nil {
+				for j := range l.glyphs {
+					f(&l.glyphs[j])
+				}
+			}
+		}
+	}
+	g.eachStack(func(s *undoStack) {
+		entries(s.undo)
+		entries(s.redo)
+	})
+}
+
+// setUndoDepth zmienia głębokość historii i od razu obcina wszystkie stosy
+func (g *Game) setUndoDepth(depth int) {
+	g.undoDepth = clampInt(depth, 10, 500)
+	g.eachStack(func(s *undoStack) { s.trim(g.undoDepth) })
+}
+
+// listUndoStack - historia listy znaków; w trybie animacji pusta
+// (cofanie dotyczy tylko klatki na siatce)
+func (g *Game) listUndoStack() *undoStack {
+	if g.spriteMode() {
+		return &undoStack{}
+	}
+	return &g.listHist
+}
+
+// undo cofa ostatnią zmianę (siatki aktywnego znaku lub listy znaków)
+func (g *Game) undo() {
+	h, lh := g.cellHist(), g.listUndoStack()
+	if top(h.undo) < 0 && top(lh.undo) < 0 {
+		return
+	}
+
+	if top(h.undo) > top(lh.undo) {
+		e := pop(&h.undo)
+		h.redo = append(h.redo, undoEntry{seq: e.seq, cells: Glyph(g.cells)})
+		g.cells = e.cells
+	} else {
+		e := pop(&lh.undo)
+		lh.redo = append(lh.redo, undoEntry{seq: e.seq, cells: Glyph(g.cells), list: g.listSnapshot()})
+		g.restoreList(e.list)
+		g.cells = e.cells
+	}
+	g.updatePreviewText()
+}
+
+// redo ponawia ostatnio cofniętą zmianę
+func (g *Game) redo() {
+	h, lh := g.cellHist(), g.listUndoStack()
+	if len(h.redo) == 0 && len(lh.redo) == 0 {
+		return
+	}
+
+	// ponawiamy najpierw zmianę cofniętą jako ostatnia (najniższy seq)
+	cellNext := len(h.redo) > 0 && (len(lh.redo) == 0 || top(h.redo) < top(lh.redo))
+	if cellNext {
+		e := pop(&h.redo)
+		h.undo = append(h.undo, undoEntry{seq: e.seq, cells: Glyph(g.cells)})
+		g.cells = e.cells
+	} else {
+		e := pop(&lh.redo)
+		lh.undo = append(lh.undo, undoEntry{seq: e.seq, cells: Glyph(g.cells), list: g.listSnapshot()})
+		g.restoreList(e.list)
+		g.cells = e.cells
+	}
+	g.updatePreviewText()
+}
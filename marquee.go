@@ -0,0 +1,218 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: marquee.go
+
+Przewijany tekst w podglądzie animacji (pod suwakiem prędkości):
+- pole "Tekst": wiadomość składana ze znaków czcionki wg kodów
+  (kod = pierwszy kod + indeks), edytowany znak widać od razu
+- znaki spoza czcionki są puste (szerokość pustego znaku) i liczone w opisie
+- symulowany pasek 4 matryc (32 kolumny x wysokość znaku), prędkość
+  z suwaka (g.animSpeed), kierunek z przycisku (g.animDir)
+- pusty tekst = bieżący znak powtórzony 4 razy
+- tekst przesuwa się o całą kolumnę na krok, jak na prawdziwych matrycach
+- "Matryce: podgląd animacji" w "Opcjach": każde okno paska idzie przez
+  SendFrame do łańcucha MAX7219 (4 matryce 8x8) zamiast znaku z sąsiadami
+- wybrany efekt (effects.go) zastępuje przewijanie w podglądzie i na matrycach
+
+Układ znaków (czcionka proporcjonalna, kerning) - proportional.go.
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// szerokość symulowanego paska w kolumnach (4 matryce)
+const MarqueeCols = 4 * MatrixSize
+
+// podgląd przewijanego tekstu
+const (
+	marqueeX = 12
+	marqueeH = 56
+)
+
+// marqueeY - górna krawędź podglądu (pod suwakiem prędkości)
+func marqueeY() int {
+	return EditorSize + 280
+}
+
+// marqueeLED - rozmiar diody podglądu (wysokie znaki - mniejsze diody)
+func marqueeLED() int {
+	return min(int(float64(EditorSize/8)*AnimCellScale), marqueeH/GridH)
+}
+
+// messageGlyph - znak czcionki dla kodu; edytowany znak z siatki
+func (g *Game) messageGlyph(code int) (Glyph, int, bool) {
+	i := code - g.firstCode
+	switch {
+	case i == g.activeGlyph:
+		return g.glyphCells(), i, true
+	case i >= 0 && i < len(g.glyphs):
+		return g.glyphs[i], i, true
+	}
+	return Glyph{}, -1, false
+}
+
+// messageGlyphs - znaki tekstu z pola (indeksy dla układu); pusty tekst =
+// bieżący znak z siatki; missing = brakujące znaki
+func (g *Game) messageGlyphs() (glyphs []Glyph, idx []int, missing int) {
+	if g.message.text == "" {
+		return []Glyph{g.glyphCells()}, []int{g.activeGlyph}, 0
+	}
+	for _, r := range g.message.text {
+		gl, i, ok := g.messageGlyph(int(r))
+		if !ok && r != ' ' {
+			missing++
+		}
+		glyphs = append(glyphs, gl)
+		idx = append(idx, i)
+	}
+	return glyphs, idx, missing
+}
+
+// messageStrip - tekst z pola ułożony znakami czcionki; pusty tekst =
+// bieżący znak powtórzony 4 razy
+func (g *Game) messageStrip() (rows [][]int, missing int) {
+	glyphs, idx, missing := g.messageGlyphs()
+	if g.message.text == "" {
+		cur, a := glyphs[0], idx[0]
+		return g.layoutGlyphs([]Glyph{cur, cur, cur, cur}, []int{a, a, a, a}), 0
+	}
+	return g.layoutGlyphs(glyphs, idx), missing
+}
+
+// stripWidth - szerokość ułożonego paska w kolumnach
+func stripWidth(rows [][]int) int {
+	if len(rows) == 0 {
+		return 0
+	}
+	return len(rows[0])
+}
+
+// restartMarquee ustawia tekst na początku przejazdu (wjeżdża z boku)
+func (g *Game) restartMarquee() {
+	g.marqueeStep = 0
+	if g.animDir < 0 {
+		g.animX = MarqueeCols
+	} else {
+		strip, _ := g.messageStrip()
+		g.animX = -float64(stripWidth(strip))
+	}
+}
+
+// updateAnimation przesuwa przewijany tekst o jedną kolumnę na krok
+// (g.animX - kolumna początku tekstu względem lewej krawędzi paska);
+// suwak prędkości ustala, co ile klatek wypada krok
+func (g *Game) updateAnimation() {
+	if !g.animRunning {
+		return
+	}
+	if g.effectActive() {
+		g.updateEffect() // effects.go
+		return
+	}
+	g.marqueeStep += g.animSpeed * 0.5
+	for g.marqueeStep >= 1 {
+		g.marqueeStep--
+		g.stepMarquee()
+	}
+}
+
+// stepMarquee - jeden krok (kolumna) przewijanego tekstu z zawijaniem
+func (g *Game) stepMarquee() {
+	strip, _ := g.messageStrip()
+	w := float64(stripWidth(strip))
+	g.animX += float64(g.animDir)
+	if g.animDir < 0 && g.animX < -w {
+		g.animX = MarqueeCols
+	} else if g.animDir > 0 && g.animX > MarqueeCols {
+		g.animX = -w
+	}
+}
+
+// marqueeWindow - widoczny fragment paska: MarqueeCols kolumn x GridH wierszy;
+// przy wybranym efekcie - klatka efektu na każdej matrycy
+func (g *Game) marqueeWindow() [][]int {
+	if g.effectActive() {
+		return tileFrame(g.effectFrame(), GridH)
+	}
+	strip, _ := g.messageStrip()
+	ofs := int(g.animX)
+	win := make([][]int, GridH)
+	for y := range win {
+		win[y] = make([]int, MarqueeCols)
+		for x := range win[y] {
+			if sx := x - ofs; sx >= 0 && sx < stripWidth(strip) {
+				win[y][x] = strip[y][sx]
+			}
+		}
+	}
+	return win
+}
+
+// buildMarqueeFrame - ramka dla matryc: okno paska, górne MatrixSize wierszy
+func (g *Game) buildMarqueeFrame() [][]int {
+	win := g.marqueeWindow()
+	frame := make([][]int, MatrixSize)
+	for y := range frame {
+		frame[y] = make([]int, MarqueeCols)
+		if y < len(win) {
+			copy(frame[y], win[y])
+		}
+	}
+	return frame
+}
+
+// handleMarqueeClick - LPM na polu "Tekst"
+func (g *Game) handleMarqueeClick(x, y int) bool {
+	ix := marqueeX + MarqueeCols*marqueeLED() + 12
+	if x >= ix && x < ix+170 && y >= marqueeY() && y < marqueeY()+24 {
+		g.focusInput(&g.message)
+		return true
+	}
+	return false
+}
+
+// drawMarquee rysuje symulowany pasek matryc i pole tekstu
+func (g *Game) drawMarquee(screen *ebiten.Image) {
+	led := marqueeLED()
+	px, py := marqueeX, marqueeY()
+	fillRect(screen, px, py, MarqueeCols*led, marqueeH, color.RGBA{R: 0x08, G: 0x08, B: 0x08, A: 0xff})
+
+	for y, row := range g.marqueeWindow() {
+		for x, v := range row {
+			col := color.Color(color.RGBA{R: 0x1A, G: 0x1A, B: 0x1E, A: 0xff}) // zgaszona dioda
+			if v != 0 {
+				col = cellColor(v)
+			}
+			fillRect(screen, px+x*led+1, py+y*led+1, led-2, led-2, col)
+		}
+	}
+	// granice matryc
+	for m := 1; m < MarqueeCols/MatrixSize; m++ {
+		fillRect(screen, px+m*MatrixSize*led, py, 1, GridH*led, color.RGBA{R: 0x30, G: 0x30, B: 0x36, A: 0xff})
+	}
+
+	ix := px + MarqueeCols*led + 12
+	g.message.draw(screen, ix, py, 170, 24, g.focus == &g.message)
+	strip, missing := g.messageStrip()
+	info := fmt.Sprintf("Tekst: %d kol.", stripWidth(strip))
+	if g.effectActive() {
+		info = fmt.Sprintf("Efekt: %d/%d kl.", g.fxPos+1, len(g.fxFrames))
+	}
+	if missing > 0 {
+		info += fmt.Sprintf(", brak %d zn.", missing)
+	}
+	if g.marqueeHW {
+		info += " -> matryce"
+	}
+	ebitenutil.DebugPrintAt(screen, info, ix, py+28)
+}
@@ -0,0 +1,355 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: metrics.go
+
+Metryka czcionki (zakładka "Metryka" panelu bocznego):
+- linia bazowa (wiersz, na którym stoją litery), ascent (wiersze od góry
+  liter do linii bazowej włącznie) i descent (wiersze pod linią bazową);
+  na siatce jako linie pomocnicze ("Linie metryki na siatce" w "Opcjach")
+- kerning: korekta odstępu (w kolumnach, ujemna = bliżej) dla par kodów
+  znaków; pole "Lewy" / "Prawy" przyjmuje 65, 0x41, 'A lub sam znak,
+  kliknięcie pary na liście wczytuje ją do edycji
+- eksport C: stałe FONT_BASELINE / FONT_ASCENT / FONT_DESCENT, posortowana
+  tabela font_kerning[] i funkcja font_kern(lewy, prawy)
+
+Kerning działa w układzie tekstu czcionki proporcjonalnej (proportional.go).
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// kernPair - korekta odstępu między znakami o kodach Left i Right
+type kernPair struct {
+	Left   int `json:"left"`
+	Right  int `json:"right"`
+	Adjust int `json:"adjust"`
+}
+
+// fontMetrics - metryka czcionki (plik projektu, eksport)
+type fontMetrics struct {
+	Baseline int `json:"baseline"`
+	Ascent   int `json:"ascent"`
+	Descent  int `json:"descent"`
+}
+
+// układ listy par kerningu
+const kernRowH = 16
+
+// metrics zwraca bieżącą metrykę
+func (g *Game) metrics() fontMetrics {
+	return fontMetrics{g.baseline, g.ascent, g.descent}
+}
+
+// defaultMetrics - ascent do górnej krawędzi siatki, descent do dolnej
+func (g *Game) defaultMetrics() {
+	g.baseline = clampInt(g.baseline, 0, GridH-1)
+	g.ascent = g.baseline + 1
+	g.descent = GridH - 1 - g.baseline
+}
+
+// clampMetrics utrzymuje ascent / descent w granicach siatki
+func (g *Game) clampMetrics() {
+	g.baseline = clampInt(g.baseline, 0, GridH-1)
+	g.ascent = clampInt(g.ascent, 1, g.baseline+1)
+	g.descent = clampInt(g.descent, 0, GridH-1-g.baseline)
+}
+
+// setBaseline przesuwa linię bazową
+func (g *Game) setBaseline(v int) {
+	g.baseline = v
+	g.clampMetrics()
+	g.updatePreviewText()
+}
+
+// parseCodeInput - kod znaku z pola: sam znak, 65, 0x41 lub 'A
+func parseCodeInput(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if r := []rune(s); len(r) == 1 && !unicode.IsDigit(r[0]) {
+		return int(r[0]), nil
+	}
+	return parseGlyphRef(s)
+}
+
+// codeInputText - kod jako tekst pola (znak ASCII lub liczba)
+func codeInputText(code int) string {
+	if code > 0x20 && code < 0x7F {
+		return string(rune(code))
+	}
+	return fmt.Sprint(code)
+}
+
+// kernIndex - pozycja pary w posortowanej liście (found = para istnieje)
+func (g *Game) kernIndex(left, right int) (int, bool) {
+	i := sort.Search(len(g.kerning), func(i int) bool {
+		k := g.kerning[i]
+		return k.Left > left || k.Left == left && k.Right >= right
+	})
+	return i, i < len(g.kerning) && g.kerning[i].Left == left && g.kerning[i].Right == right
+}
+
+// normalizeKerning - lista par posortowana wg (Left, Right), jak zakłada
+// kernIndex; duplikaty - zostaje ostatni, pary z korektą 0 usunięte
+func normalizeKerning(pairs []kernPair) []kernPair {
+	sort.SliceStable(pairs, func(i, j int) bool {
+		a, b := pairs[i], pairs[j]
+		return a.Left < b.Left || a.Left == b.Left && a.Right < b.Right
+	})
+	var out []kernPair
+	for i, k := range pairs {
+		if i+1 < len(pairs) && pairs[i+1].Left == k.Left && pairs[i+1].Right == k.Right {
+			continue
+		}
+		if k.Adjust != 0 {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// kernAdjust - korekta odstępu między kodami left i right (0 = brak pary)
+func (g *Game) kernAdjust(left, right int) int {
+	if i, ok := g.kernIndex(left, right); ok {
+		return g.kerning[i].Adjust
+	}
+	return 0
+}
+
+// setKernPair ustawia parę; korekta 0 usuwa parę z listy
+func (g *Game) setKernPair(left, right, adjust int) {
+	i, ok := g.kernIndex(left, right)
+	switch {
+	case ok && adjust == 0:
+		g.kerning = append(g.kerning[:i:i], g.kerning[i+1:]...)
+	case ok:
+		g.kerning[i].Adjust = adjust
+	case adjust != 0:
+		g.kerning = append(g.kerning[:i:i], append([]kernPair{{left, right, adjust}}, g.kerning[i:]...)...)
+	}
+	g.updatePreviewText()
+}
+
+// kernInputPair - kody z pól "Lewy" / "Prawy"
+func (g *Game) kernInputPair() (left, right int, err error) {
+	if left, err = parseCodeInput(g.kernLeft.text); err != nil {
+		return 0, 0, fmt.Errorf("lewy: %v", err)
+	}
+	if right, err = parseCodeInput(g.kernRight.text); err != nil {
+		return 0, 0, fmt.Errorf("prawy: %v", err)
+	}
+	return left, right, nil
+}
+
+// applyKernInput - "Ustaw" / "Usuń": zapis pary z pól edycji
+func (g *Game) applyKernInput(adjust int) {
+	left, right, err := g.kernInputPair()
+	if err != nil {
+		g.lastExport = "Kerning: " + err.Error()
+		return
+	}
+	g.setKernPair(left, right, adjust)
+	g.lastExport = fmt.Sprintf("Kerning %s %s: %d", codeLabel(left), codeLabel(right), adjust)
+}
+
+// onKernInput - zmiana pól: korekta istniejącej pary trafia do edycji
+func (g *Game) onKernInput(string) {
+	if left, right, err := g.kernInputPair(); err == nil {
+		g.kernValue = g.kernAdjust(left, right)
+	}
+}
+
+// selectKernPair wczytuje parę z listy do pól edycji
+func (g *Game) selectKernPair(i int) {
+	k := g.kerning[i]
+	g.kernLeft.text = codeInputText(k.Left)
+	g.kernRight.text = codeInputText(k.Right)
+	g.kernValue = k.Adjust
+}
+
+// metricButtons - przyciski zakładki "Metryka"
+func (g *Game) metricButtons() []uiButton {
+	x0, y0, _, _ := sideRect()
+	step := func(y int, fn func(d int)) []uiButton {
+		return []uiButton{
+			{x0 + 220, y, 34, 22, "-", func() { fn(-1) }},
+			{x0 + 258, y, 34, 22, "+", func() { fn(1) }},
+		}
+	}
+	var buttons []uiButton
+	buttons = append(buttons, step(y0+28, func(d int) { g.setBaseline(g.baseline + d) })...)
+	buttons = append(buttons, step(y0+54, func(d int) {
+		g.ascent += d
+		g.clampMetrics()
+		g.updatePreviewText()
+	})...)
+	buttons = append(buttons, step(y0+80, func(d int) {
+		g.descent += d
+		g.clampMetrics()
+		g.updatePreviewText()
+	})...)
+	buttons = append(buttons, step(y0+172, func(d int) { g.kernValue = clampInt(g.kernValue+d, -GridW, GridW) })...)
+	buttons = append(buttons,
+		uiButton{x0 + 8, y0 + 198, 140, 22, "Ustaw parę", func() { g.applyKernInput(g.kernValue) }},
+		uiButton{x0 + 152, y0 + 198, 140, 22, "Usuń parę", func() { g.applyKernInput(0) }},
+	)
+	return buttons
+}
+
+// kernListRect - obszar listy par kerningu
+func kernListRect() (x, y, w, h int) {
+	x0, y0, _, sh := sideRect()
+	return x0 + 8, y0 + 228, OptionsW - 16, sh - 232
+}
+
+// scrollKerning przewija listę par kółkiem myszy
+func (g *Game) scrollKerning(wy float64) {
+	_, _, _, h := kernListRect()
+	maxScroll := max(0, len(g.kerning)-h/kernRowH)
+	g.kernScroll = clampInt(g.kernScroll-int(wy), 0, maxScroll)
+}
+
+// handleMetricsClick - kliknięcie w zakładce "Metryka"
+func (g *Game) handleMetricsClick(x, y, dir int) {
+	if dir < 0 {
+		return
+	}
+	x0, y0, _, _ := sideRect()
+	switch {
+	case y >= y0+140 && y < y0+164 && x >= x0+50 && x < x0+130:
+		g.focusInput(&g.kernLeft)
+		return
+	case y >= y0+140 && y < y0+164 && x >= x0+200 && x < x0+280:
+		g.focusInput(&g.kernRight)
+		return
+	}
+	if clickButtons(g.metricButtons(), x, y) {
+		return
+	}
+	lx, ly, lw, lh := kernListRect()
+	if x >= lx && x < lx+lw && y >= ly && y < ly+lh {
+		if i := g.kernScroll + (y-ly)/kernRowH; i < len(g.kerning) {
+			g.selectKernPair(i)
+		}
+	}
+}
+
+// drawMetricsTab rysuje zakładkę "Metryka"
+func (g *Game) drawMetricsTab(screen *ebiten.Image) {
+	x0, y0, _, _ := sideRect()
+	drawText(screen, "Metryka czcionki", x0+8, y0+20)
+	drawText(screen, fmt.Sprintf("Linia bazowa: wiersz %d", g.baseline), x0+8, y0+44)
+	drawText(screen, fmt.Sprintf("Ascent: %d wierszy", g.ascent), x0+8, y0+70)
+	drawText(screen, fmt.Sprintf("Descent: %d wierszy", g.descent), x0+8, y0+96)
+
+	drawText(screen, fmt.Sprintf("Kerning: %d par", len(g.kerning)), x0+8, y0+130)
+	drawText(screen, "Lewy:", x0+8, y0+158)
+	g.kernLeft.draw(screen, x0+50, y0+140, 80, 24, g.focus == &g.kernLeft)
+	drawText(screen, "Prawy:", x0+148, y0+158)
+	g.kernRight.draw(screen, x0+200, y0+140, 80, 24, g.focus == &g.kernRight)
+	drawText(screen, fmt.Sprintf("Korekta odstępu: %+d kol.", g.kernValue), x0+8, y0+188)
+
+	for _, b := range g.metricButtons() {
+		b.draw(screen, btnColor)
+	}
+
+	// lista par
+	lx, ly, lw, lh := kernListRect()
+	fillRect(screen, lx, ly, lw, lh, color.RGBA{R: 0x0E, G: 0x0E, B: 0x10, A: 0xff})
+	view := screen.SubImage(image.Rect(lx, ly, lx+lw, ly+lh)).(*ebiten.Image)
+	left, right, err := g.kernInputPair()
+	for r := 0; r*kernRowH < lh; r++ {
+		i := g.kernScroll + r
+		if i >= len(g.kerning) {
+			break
+		}
+		k := g.kerning[i]
+		y := ly + r*kernRowH
+		if err == nil && k.Left == left && k.Right == right {
+			fillRect(view, lx, y, lw, kernRowH, btnColorAct)
+		}
+		ebitenutil.DebugPrintAt(view, fmt.Sprintf("%-6s + %-6s %+d", codeLabel(k.Left), codeLabel(k.Right), k.Adjust), lx+4, y)
+	}
+}
+
+// drawMetricGuides rysuje linie metryki na siatce edytora
+func (g *Game) drawMetricGuides(screen *ebiten.Image) {
+	if !g.showMetrics {
+		return
+	}
+	w := GridW * CellSize
+	line := func(row int, col color.RGBA) {
+		fillRect(screen, 0, row*CellSize-1, w, 2, col)
+	}
+	line(g.baseline+1-g.ascent, color.RGBA{R: 0x40, G: 0xA0, B: 0xFF, A: 0xC0})
+	line(g.baseline+1+g.descent, color.RGBA{R: 0x40, G: 0xA0, B: 0xFF, A: 0xC0})
+	line(g.baseline+1, color.RGBA{R: 0xFF, G: 0x50, B: 0x50, A: 0xE0})
+}
+
+// writeMetrics dopisuje stałe metryki czcionki
+func writeMetrics(b *strings.Builder, m fontMetrics) {
+	b.WriteString(fmt.Sprintf("#define FONT_BASELINE %d // wiersz linii bazowej\n", m.Baseline))
+	b.WriteString(fmt.Sprintf("#define FONT_ASCENT   %d\n", m.Ascent))
+	b.WriteString(fmt.Sprintf("#define FONT_DESCENT  %d\n\n", m.Descent))
+}
+
+// writeKerning dopisuje posortowaną tabelę par kerningu i funkcję font_kern()
+// (wyszukiwanie binarne); kody do 255 zajmują bajt, większe uint16
+func writeKerning(b *strings.Builder, pairs []kernPair, progmem bool) {
+	if len(pairs) == 0 {
+		return
+	}
+	codeBits := 8
+	for _, k := range pairs {
+		if k.Left > 0xFF || k.Right > 0xFF {
+			codeBits = 16
+		}
+	}
+	ctype := ctypeBits(codeBits)
+	pm := ""
+	if progmem {
+		pm = " PROGMEM"
+	}
+
+	b.WriteString("\n// kerning: pary (lewy, prawy) posortowane rosnąco, korekta odstępu w kolumnach\n")
+	b.WriteString(fmt.Sprintf("#define FONT_KERN_PAIRS %d\n", len(pairs)))
+	b.WriteString(fmt.Sprintf("typedef struct { %s left, right; int8_t adjust; } font_kern_t;\n", ctype))
+	b.WriteString(fmt.Sprintf("const font_kern_t font_kerning[FONT_KERN_PAIRS]%s = {\n", pm))
+	for _, k := range pairs {
+		b.WriteString(fmt.Sprintf("  { 0x%02X, 0x%02X, %d },\n", k.Left, k.Right, k.Adjust))
+	}
+	b.WriteString("};\n\n")
+
+	field := func(name string) string {
+		ref := "font_kerning[mid]." + name
+		if !progmem {
+			return ref
+		}
+		if codeBits == 16 && name != "adjust" {
+			return "pgm_read_word(&" + ref + ")"
+		}
+		return "pgm_read_byte(&" + ref + ")"
+	}
+	b.WriteString("static inline int8_t font_kern(uint16_t left, uint16_t right)\n{\n")
+	b.WriteString("  uint16_t lo = 0, hi = FONT_KERN_PAIRS;\n")
+	b.WriteString("  while (lo < hi) {\n")
+	b.WriteString("    uint16_t mid = (lo + hi) / 2;\n")
+	b.WriteString(fmt.Sprintf("    uint16_t l = %s, r = %s;\n", field("left"), field("right")))
+	b.WriteString(fmt.Sprintf("    if (l == left && r == right) return (int8_t)%s;\n", field("adjust")))
+	b.WriteString("    if (l < left || (l == left && r < right)) lo = mid + 1;\n")
+	b.WriteString("    else hi = mid;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return 0;\n}\n")
+}
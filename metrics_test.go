@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestNormalizeKerning - sortowanie wg (Left, Right), z duplikatów zostaje
+// ostatni, pary z korektą 0 znikają
+func TestNormalizeKerning(t *testing.T) {
+	in := []kernPair{
+		{Left: 'V', Right: 'A', Adjust: -1},
+		{Left: 'A', Right: 'V', Adjust: -2},
+		{Left: 'A', Right: 'T', Adjust: -1},
+		{Left: 'A', Right: 'V', Adjust: -3},
+		{Left: 'L', Right: 'T', Adjust: 0},
+		{Left: 'T', Right: 'o', Adjust: 2},
+		{Left: 'T', Right: 'o', Adjust: 0},
+	}
+	want := []kernPair{
+		{Left: 'A', Right: 'T', Adjust: -1},
+		{Left: 'A', Right: 'V', Adjust: -3},
+		{Left: 'V', Right: 'A', Adjust: -1},
+	}
+	if got := normalizeKerning(in); !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeKerning = %v, want %v", got, want)
+	}
+	if got := normalizeKerning(nil); len(got) != 0 {
+		t.Errorf("normalizeKerning(nil) = %v", got)
+	}
+}
+
+// TestKernIndex - pozycja pary w posortowanej liście
+func TestKernIndex(t *testing.T) {
+	g := &Game{kerning: []kernPair{
+		{Left: 'A', Right: 'T', Adjust: -1},
+		{Left: 'A', Right: 'V', Adjust: -3},
+		{Left: 'V', Right: 'A', Adjust: -1},
+	}}
+	tests := []struct {
+		left, right int
+		i           int
+		found       bool
+	}{
+		{'A', 'T', 0, true},
+		{'A', 'V', 1, true},
+		{'V', 'A', 2, true},
+		{' ', ' ', 0, false},
+		{'A', 'U', 1, false},
+		{'B', 'A', 2, false},
+		{'Z', 'Z', 3, false},
+	}
+	for _, tt := range tests {
+		i, found := g.kernIndex(tt.left, tt.right)
+		if i != tt.i || found != tt.found {
+			t.Errorf("kernIndex(%c,%c) = %d %v, want %d %v", tt.left, tt.right, i, found, tt.i, tt.found)
+		}
+		if g.kernAdjust(tt.left, tt.right) != 0 != tt.found {
+			t.Errorf("kernAdjust(%c,%c) niezgodny z kernIndex", tt.left, tt.right)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: onion.go
+
+Onion skin ("Onion skin" w "Opcjach"): poprzedni i/lub następny znak
+rysowany na siatce jako półprzezroczyste "duchy" - pomaga rysować
+klatka po klatce albo rodzinę podobnych znaków:
+- poprzedni na czerwono, następny na niebiesko, krycie w "Onion skin: krycie"
+- duch widać tylko w pustych komórkach, g.cells się nie zmienia
+- w trybie "Edycja klatek" sąsiadami są klatki animacji zamiast znaków
+
+*/
+
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// tryby onion skin
+const (
+	OnionOff = iota
+	OnionPrev
+	OnionNext
+	OnionBoth
+
+	onionModeCount
+)
+
+var onionModeNames = [onionModeCount]string{"wył.", "poprzedni", "następny", "oba"}
+
+// domyślne krycie duchów (%)
+const DefaultOnionAlpha = 35
+
+// kolory duchów: poprzedni / następny
+var (
+	onionPrevColor = color.RGBA{R: 0xFF, G: 0x50, B: 0x50, A: 0xff}
+	onionNextColor = color.RGBA{R: 0x40, G: 0xA0, B: 0xFF, A: 0xff}
+)
+
+// onionNeighbours - poprzedni i następny znak (lub klatka); nil = brak
+func (g *Game) onionNeighbours() (prev, next *Glyph) {
+	list, cur := g.glyphs, g.activeGlyph
+	if g.spriteMode() {
+		list, cur = g.sprite.glyphs(), g.spriteCur
+	}
+	if i := cur - 1; i >= 0 && i < len(list) {
+		prev = &list[i]
+	}
+	if i := cur + 1; i >= 0 && i < len(list) {
+		next = &list[i]
+	}
+	return prev, next
+}
+
+// drawOnionSkin rysuje duchy sąsiednich znaków w pustych komórkach siatki
+func (g *Game) drawOnionSkin(screen *ebiten.Image) {
+	if g.onion == OnionOff || g.onionAlpha == 0 {
+		return
+	}
+	prev, next := g.onionNeighbours()
+	if g.onion == OnionNext {
+		prev = nil
+	}
+	if g.onion == OnionPrev {
+		next = nil
+	}
+
+	ghost := func(c color.RGBA) color.Color {
+		return color.NRGBA{R: c.R, G: c.G, B: c.B, A: uint8(g.onionAlpha * 255 / 100)}
+	}
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			if g.cells[y][x] != 0 {
+				continue
+			}
+			px, py := x*CellSize+3, y*CellSize+3
+			if prev != nil && prev[y][x] != 0 {
+				fillRect(screen, px, py, CellSize-6, CellSize-6, ghost(onionPrevColor))
+			}
+			if next != nil && next[y][x] != 0 {
+				fillRect(screen, px, py, CellSize-6, CellSize-6, ghost(onionNextColor))
+			}
+		}
+	}
+}
@@ -0,0 +1,301 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: options.go
+
+Zakładka "Opcje" panelu bocznego.
+Każda opcja to jeden wiersz "nazwa: wartość":
+- LPM na wierszu  -> następna wartość
+- PPM na wierszu  -> poprzednia wartość
+- kółko myszy     -> przewijanie listy
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// wysokość wiersza listy opcji
+const OptionsRowH = 20
+
+// option - jedna pozycja panelu opcji
+type option struct {
+	label string
+	value func() string
+	next  func()
+	prev  func()
+}
+
+// cycle przesuwa wartość v o d w zakresie 0..n-1 z zawijaniem
+func cycle(v *int, n, d int) {
+	*v = ((*v+d)%n + n) % n
+}
+
+// toggle - opcja typu wł./wył.
+func toggle(label string, v *bool) option {
+	return option{
+		label: label,
+		value: func() string { return onOff(*v) },
+		next:  func() { *v = !*v },
+		prev:  func() { *v = !*v },
+	}
+}
+
+func onOff(v bool) string {
+	if v {
+		return "wł."
+	}
+	return "wył."
+}
+
+// options zwraca aktualną listę opcji
+func (g *Game) options() []option {
+	return append([]option{
+		{
+			label: "Historia cofania",
+			value: func() string { return fmt.Sprintf("%d kroków", g.undoDepth) },
+			next:  func() { g.setUndoDepth(g.undoDepth + 10) },
+			prev:  func() { g.setUndoDepth(g.undoDepth - 10) },
+		},
+		{
+			label: "Szerokość znaku",
+			value: func() string { return fmt.Sprintf("%d px", GridW) },
+			next:  func() { g.setGlyphSize(GridW+1, GridH) },
+			prev:  func() { g.setGlyphSize(GridW-1, GridH) },
+		},
+		{
+			label: "Wysokość znaku",
+			value: func() string { return fmt.Sprintf("%d px", GridH) },
+			next:  func() { g.setGlyphSize(GridW, GridH+1) },
+			prev:  func() { g.setGlyphSize(GridW, GridH-1) },
+		},
+		{
+			label: "1-bit: wiersz > 8 px",
+			value: func() string {
+				if g.rowWords {
+					return "uint16"
+				}
+				return "bajty"
+			},
+			next: func() { g.rowWords = !g.rowWords },
+			prev: func() { g.rowWords = !g.rowWords },
+		},
+		toggle("Czcionka proporcjonalna", &g.proportional),
+		{
+			label: "Odstęp między znakami",
+			value: func() string { return fmt.Sprintf("%d kol.", g.letterSpacing) },
+			next:  func() { g.letterSpacing = clampInt(g.letterSpacing+1, 0, GridW) },
+			prev:  func() { g.letterSpacing = clampInt(g.letterSpacing-1, 0, GridW) },
+		},
+		{
+			label: "Kod pierwszego znaku",
+			value: func() string { return fmt.Sprintf("%d (0x%02X)", g.firstCode, g.firstCode) },
+			next:  func() { g.firstCode = clampInt(g.firstCode+1, 0, 0xFFFF) },
+			prev:  func() { g.firstCode = clampInt(g.firstCode-1, 0, 0xFFFF) },
+		},
+		{
+			label: "Linia bazowa (wiersz)",
+			value: func() string { return fmt.Sprintf("%d", g.baseline) },
+			next:  func() { g.setBaseline(g.baseline + 1) },
+			prev:  func() { g.setBaseline(g.baseline - 1) },
+		},
+		toggle("Linie metryki na siatce", &g.showMetrics),
+		{
+			label: "Onion skin",
+			value: func() string { return onionModeNames[g.onion] },
+			next:  func() { cycle(&g.onion, onionModeCount, 1) },
+			prev:  func() { cycle(&g.onion, onionModeCount, -1) },
+		},
+		percent("Onion skin: krycie", &g.onionAlpha, 5),
+		toggle("Matryce: podgląd animacji", &g.marqueeHW),
+		{
+			label: "Margines z lewej",
+			value: func() string { return fmt.Sprintf("%d kol.", g.batchPad) },
+			next:  func() { g.batchPad = clampInt(g.batchPad+1, 0, GridW-1) },
+			prev:  func() { g.batchPad = clampInt(g.batchPad-1, 0, GridW-1) },
+		},
+		toggle("Podgląd eksportu: PROGMEM", &g.previewProgmem),
+		toggle("Indeks: tylko użyte kolory", &g.indexUsedOnly),
+		{
+			label: "Indeks: format palety",
+			value: func() string { return exportModeName(g.indexColorMode) },
+			next:  func() { g.indexColorMode = nextColorFormat(g.indexColorMode, 1) },
+			prev:  func() { g.indexColorMode = nextColorFormat(g.indexColorMode, -1) },
+		},
+		{
+			label: "WS2812: kolejność",
+			value: func() string { return ws2812OrderName(g.ws2812Order) },
+			next:  func() { cycle(&g.ws2812Order, 2, 1) },
+			prev:  func() { cycle(&g.ws2812Order, 2, -1) },
+		},
+		percent("WS2812: jasność eksportu", &g.ws2812Brightness, 5),
+		{
+			label: "WS2812: pierwsza dioda",
+			value: func() string { return originName(g.ws2812Layout.Origin) },
+			next:  func() { cycle(&g.ws2812Layout.Origin, 4, 1) },
+			prev:  func() { cycle(&g.ws2812Layout.Origin, 4, -1) },
+		},
+		{
+			label: "WS2812: kierunek",
+			value: func() string {
+				if g.ws2812Layout.Vertical {
+					return "kolumny"
+				}
+				return "wiersze"
+			},
+			next: func() { g.ws2812Layout.Vertical = !g.ws2812Layout.Vertical },
+			prev: func() { g.ws2812Layout.Vertical = !g.ws2812Layout.Vertical },
+		},
+		toggle("WS2812: zig-zag", &g.ws2812Layout.Serpentine),
+		{
+			label: "WS2812: panel",
+			value: func() string {
+				w, h := g.ws2812Layout.Panel()
+				return fmt.Sprintf("%dx%d diod", w, h)
+			},
+			next: func() { g.cycleWS2812Panel(1) },
+			prev: func() { g.cycleWS2812Panel(-1) },
+		},
+		{
+			label: "WS2812: panele",
+			value: func() string {
+				w, h := g.ws2812Layout.Size()
+				return fmt.Sprintf("%dx%d (%dx%d)", g.ws2812Layout.TilesX, g.ws2812Layout.TilesY, w, h)
+			},
+			next: func() { g.cycleWS2812Tiling(1) },
+			prev: func() { g.cycleWS2812Tiling(-1) },
+		},
+		toggle("WS2812: panele zig-zag", &g.ws2812Layout.TileSerpentine),
+		{
+			label: "LED: gamma",
+			value: func() string { return fmt.Sprintf("%.1f", g.ledCalib.Gamma) },
+			next:  func() { g.ledCalib.Gamma = clampFloat(g.ledCalib.Gamma+0.1, 1, 3) },
+			prev:  func() { g.ledCalib.Gamma = clampFloat(g.ledCalib.Gamma-0.1, 1, 3) },
+		},
+		percent("LED: balans R", &g.ledCalib.WhiteR, 5),
+		percent("LED: balans G", &g.ledCalib.WhiteG, 5),
+		percent("LED: balans B", &g.ledCalib.WhiteB, 5),
+		percent("LED: maks. jasność", &g.ledCalib.MaxBri, 5),
+		toggle("LED: korekcja w eksporcie", &g.ws2812ExportCalib),
+		{
+			label: "Zasilanie: mA na kanał",
+			value: func() string { return fmt.Sprintf("%d mA", g.power.ChannelMA) },
+			next:  func() { g.power.ChannelMA = clampInt(g.power.ChannelMA+1, 1, 60) },
+			prev:  func() { g.power.ChannelMA = clampInt(g.power.ChannelMA-1, 1, 60) },
+		},
+		{
+			label: "Zasilanie: budżet",
+			value: func() string { return fmt.Sprintf("%d mA", g.power.BudgetMA) },
+			next:  func() { g.power.BudgetMA = clampInt(g.power.BudgetMA+100, 100, 10000) },
+			prev:  func() { g.power.BudgetMA = clampInt(g.power.BudgetMA-100, 100, 10000) },
+		},
+		toggle("Zasilanie: limiter jasności", &g.power.Limit),
+	}, g.effectOptions()...)
+}
+
+// cycleWS2812Tiling przełącza układ paneli z listy ws2812Tilings
+func (g *Game) cycleWS2812Tiling(d int) {
+	i := 0
+	for j, t := range ws2812Tilings {
+		if t[0] == g.ws2812Layout.TilesX && t[1] == g.ws2812Layout.TilesY {
+			i = j
+		}
+	}
+	cycle(&i, len(ws2812Tilings), d)
+	g.ws2812Layout.TilesX, g.ws2812Layout.TilesY = ws2812Tilings[i][0], ws2812Tilings[i][1]
+}
+
+// cycleWS2812Panel przełącza rozmiar panelu z listy ws2812Panels
+func (g *Game) cycleWS2812Panel(d int) {
+	i := 0
+	w, h := g.ws2812Layout.Panel()
+	for j, p := range ws2812Panels {
+		if p[0] == w && p[1] == h {
+			i = j
+		}
+	}
+	cycle(&i, len(ws2812Panels), d)
+	g.ws2812Layout.PanelW, g.ws2812Layout.PanelH = ws2812Panels[i][0], ws2812Panels[i][1]
+}
+
+// percent - opcja procentowa 0..100 zmieniana o krok step
+func percent(label string, v *int, step int) option {
+	return option{
+		label: label,
+		value: func() string { return fmt.Sprintf("%d%%", *v) },
+		next:  func() { *v = clampInt(*v+step, 0, 100) },
+		prev:  func() { *v = clampInt(*v-step, 0, 100) },
+	}
+}
+
+// optionAt zwraca indeks opcji pod kursorem lub -1
+func (g *Game) optionAt(x, y int) int {
+	if g.sideTab != TabOptions || !isInSide(x, y) {
+		return -1
+	}
+	_, oy, _, _ := sideRect()
+	i := (y-oy-28)/OptionsRowH + g.optScroll
+	if y-oy < 28 || i < 0 || i >= len(g.options()) {
+		return -1
+	}
+	return i
+}
+
+// handleOptionClick obsługuje kliknięcie w panelu opcji (dir = +1 LPM, -1 PPM)
+func (g *Game) handleOptionClick(x, y, dir int) {
+	if i := g.optionAt(x, y); i >= 0 {
+		o := g.options()[i]
+		if dir > 0 {
+			o.next()
+		} else {
+			o.prev()
+		}
+		g.updatePreviewText()
+	}
+}
+
+// scrollOptions przewija listę opcji kółkiem myszy
+func (g *Game) scrollOptions(wy float64) {
+	_, _, _, oh := sideRect()
+	visible := (oh - 28) / OptionsRowH
+	maxScroll := len(g.options()) - visible
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	g.optScroll -= int(wy)
+	if g.optScroll > maxScroll {
+		g.optScroll = maxScroll
+	}
+	if g.optScroll < 0 {
+		g.optScroll = 0
+	}
+}
+
+// drawOptions rysuje listę opcji
+func (g *Game) drawOptions(screen *ebiten.Image) {
+	ox, oy, ow, oh := sideRect()
+	drawText(screen, "Opcje (LPM / PPM)", ox+8, oy+20)
+
+	mx, my := ebiten.CursorPosition()
+	hover := g.optionAt(mx, my)
+
+	opts := g.options()
+	yy := oy + 28
+	for i := g.optScroll; i < len(opts); i++ {
+		if yy+OptionsRowH > oy+oh {
+			break
+		}
+		if i == hover {
+			fillRect(screen, ox+4, yy, ow-8, OptionsRowH-2, color.RGBA{R: 0x2A, G: 0x2A, B: 0x34, A: 0xff})
+		}
+		o := opts[i]
+		drawText(screen, fmt.Sprintf("%s: %s", o.label, o.value()), ox+8, yy+15)
+		yy += OptionsRowH
+	}
+}
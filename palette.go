@@ -8,7 +8,16 @@ Autor: SunRiver / Lothar TeaM
 
 package main
 
-import "image/color"
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
 
 var palette = []color.RGBA{
 	// OFF + white
@@ -55,3 +64,256 @@ var palette = []color.RGBA{
 	{0xff, 0x00, 0x80, 0xff}, // pink
 
 }
+
+// TrueColorFlag oznacza komórkę z dowolnym kolorem 24-bit (0xRRGGBB
+// w młodszych bitach) zamiast indeksu palety
+const TrueColorFlag = 1 << 24
+
+// trueColor zwraca wartość komórki dla koloru 24-bit
+func trueColor(c color.RGBA) int {
+	return TrueColorFlag | int(c.R)<<16 | int(c.G)<<8 | int(c.B)
+}
+
+func isTrueColor(v int) bool {
+	return v&TrueColorFlag != 0
+}
+
+// cellColor zwraca kolor dla wartości komórki (indeks palety lub kolor 24-bit),
+// wartości spoza palety traktujemy jak OFF
+func cellColor(v int) color.RGBA {
+	if isTrueColor(v) {
+		return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+	}
+	if v < 0 || v >= len(palette) {
+		return palette[0]
+	}
+	return palette[v]
+}
+
+// paletteIndex zwraca indeks palety dla wartości komórki;
+// kolor 24-bit zamieniamy na najbliższy kolor palety
+func paletteIndex(v int) int {
+	if !isTrueColor(v) {
+		return v
+	}
+	return nearestColor(cellColor(v), palette)
+}
+
+// nearestColor zwraca indeks najbliższego koloru w pal (odległość RGB)
+func nearestColor(c color.RGBA, pal []color.RGBA) int {
+	best, bestD := 0, math.MaxInt
+	for i, p := range pal {
+		dr, dg, db := int(c.R)-int(p.R), int(c.G)-int(p.G), int(c.B)-int(p.B)
+		if d := dr*dr + dg*dg + db*db; d < bestD {
+			best, bestD = i, d
+		}
+	}
+	return best
+}
+
+// maksymalna liczba kolorów palety (8-bit indeks w eksporcie)
+const MaxPalette = 256
+
+// rgbToHSV - h 0..360, s i v 0..1
+func rgbToHSV(c color.RGBA) (h, s, v float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	mx := math.Max(r, math.Max(g, b))
+	mn := math.Min(r, math.Min(g, b))
+	d := mx - mn
+	v = mx
+	if mx > 0 {
+		s = d / mx
+	}
+	if d == 0 {
+		return 0, s, v
+	}
+	switch mx {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, v
+}
+
+// hsvToRGB - odwrotność rgbToHSV
+func hsvToRGB(h, s, v float64) color.RGBA {
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	ch := func(f float64) uint8 { return uint8(math.Round((f + m) * 255)) }
+	return color.RGBA{R: ch(r), G: ch(g), B: ch(b), A: 0xff}
+}
+
+// hexColor - "#RRGGBB"
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// parseHexColor - "#RRGGBB", "RRGGBB" lub "AARRGGBB" (Paint.NET)
+func parseHexColor(s string) (color.RGBA, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 8 {
+		s = s[2:] // pomijamy kanał alfa
+	}
+	if len(s) != 6 {
+		return color.RGBA{}, fmt.Errorf("zły kolor: %q", s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("zły kolor: %q", s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// -----------------------------
+// pliki palet: GIMP .gpl, Paint.NET .txt, JSON
+// -----------------------------
+
+// loadPaletteFile wczytuje paletę, format wg rozszerzenia pliku.
+// Pozycja 0 to zawsze OFF - jeśli plik nie zaczyna się od czerni, dokładamy ją.
+func loadPaletteFile(path string) ([]color.RGBA, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pal []color.RGBA
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gpl":
+		pal, err = parseGPL(data)
+	case ".txt":
+		pal, err = parsePaintNET(data)
+	case ".json":
+		pal, err = parsePaletteJSON(data)
+	default:
+		return nil, fmt.Errorf("nieznany format palety: %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(pal) == 0 {
+		return nil, fmt.Errorf("pusta paleta: %s", path)
+	}
+
+	if pal[0] != palette[0] {
+		pal = append([]color.RGBA{palette[0]}, pal...)
+	}
+	if len(pal) > MaxPalette {
+		pal = pal[:MaxPalette]
+	}
+	return pal, nil
+}
+
+// savePaletteFile zapisuje paletę, format wg rozszerzenia pliku
+func savePaletteFile(path string, pal []color.RGBA) error {
+	var b strings.Builder
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gpl":
+		b.WriteString("GIMP Palette\nName: Sun8x8\nColumns: 9\n#\n")
+		for i, c := range pal {
+			b.WriteString(fmt.Sprintf("%3d %3d %3d\tIndex %d\n", c.R, c.G, c.B, i))
+		}
+	case ".txt":
+		b.WriteString(";paint.net Palette File\n;Generated by Sun8x8 Font Generator\n")
+		for _, c := range pal {
+			b.WriteString(fmt.Sprintf("FF%02X%02X%02X\n", c.R, c.G, c.B))
+		}
+	case ".json":
+		out := make([]string, len(pal))
+		for i, c := range pal {
+			out[i] = hexColor(c)
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+	default:
+		return fmt.Errorf("nieznany format palety: %s", path)
+	}
+
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// parseGPL - "R G B nazwa" w wierszu, nagłówek i komentarze pomijamy
+func parseGPL(data []byte) ([]color.RGBA, error) {
+	var pal []color.RGBA
+	for _, ln := range strings.Split(string(data), "\n") {
+		ln = strings.TrimSpace(ln)
+		if ln == "" || strings.HasPrefix(ln, "#") || strings.HasPrefix(ln, "GIMP") ||
+			strings.HasPrefix(ln, "Name:") || strings.HasPrefix(ln, "Columns:") {
+			continue
+		}
+		f := strings.Fields(ln)
+		if len(f) < 3 {
+			return nil, fmt.Errorf("zły wiersz palety GIMP: %q", ln)
+		}
+		var rgb [3]uint8
+		for i := 0; i < 3; i++ {
+			v, err := strconv.Atoi(f[i])
+			if err != nil || v < 0 || v > 255 {
+				return nil, fmt.Errorf("zły wiersz palety GIMP: %q", ln)
+			}
+			rgb[i] = uint8(v)
+		}
+		pal = append(pal, color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xff})
+	}
+	return pal, nil
+}
+
+// parsePaintNET - "AARRGGBB" w wierszu, komentarze od ';'
+func parsePaintNET(data []byte) ([]color.RGBA, error) {
+	var pal []color.RGBA
+	for _, ln := range strings.Split(string(data), "\n") {
+		ln = strings.TrimSpace(ln)
+		if ln == "" || strings.HasPrefix(ln, ";") {
+			continue
+		}
+		c, err := parseHexColor(ln)
+		if err != nil {
+			return nil, err
+		}
+		pal = append(pal, c)
+	}
+	return pal, nil
+}
+
+// parsePaletteJSON - tablica "#RRGGBB" (jak w exportJSON)
+func parsePaletteJSON(data []byte) ([]color.RGBA, error) {
+	var in []string
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, err
+	}
+	pal := make([]color.RGBA, 0, len(in))
+	for _, s := range in {
+		c, err := parseHexColor(s)
+		if err != nil {
+			return nil, err
+		}
+		pal = append(pal, c)
+	}
+	return pal, nil
+}
@@ -0,0 +1,382 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: palette_editor.go
+
+Zakładka "Paleta" panelu bocznego:
+- kliknięcie próbki wybiera kolor do edycji i do rysowania
+- suwaki R/G/B i H/S/V zmieniają wybrany kolor
+- dodawanie, usuwanie i przesuwanie pozycji palety
+- wczytanie / zapis palety: GIMP .gpl, Paint.NET .txt, JSON
+
+Pozycja 0 to OFF - nie można jej usunąć ani przesunąć.
+
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/sqweek/dialog"
+)
+
+// układ zakładki palety
+const (
+	palSwatch    = 24 // rozmiar próbki
+	palPerRow    = 12 // próbek w wierszu
+	palRows      = 4  // widocznych wierszy próbek
+	palSliderRow = 18 // odstęp między suwakami
+)
+
+// suwaki edycji koloru
+const (
+	palSliderR = iota
+	palSliderG
+	palSliderB
+	palSliderH
+	palSliderS
+	palSliderV
+
+	palSliderCount
+)
+
+var palSliderNames = [palSliderCount]string{"R", "G", "B", "H", "S", "V"}
+
+// paletteGeometry zwraca położenie elementów zakładki
+func paletteGeometry() (swX, swY, infoY, sliderX, sliderY, sliderW, btnY int) {
+	x0, y0, _, _ := sideRect()
+	swX = x0 + 8
+	swY = y0 + 28
+	infoY = swY + palRows*palSwatch + 18
+	sliderX = x0 + 60
+	sliderY = infoY + 10
+	sliderW = OptionsW - 80
+	btnY = sliderY + palSliderCount*palSliderRow + 4
+	return
+}
+
+// paletteButtons - przyciski pod suwakami
+func (g *Game) paletteButtons() []uiButton {
+	x0, _, _, _ := sideRect()
+	_, _, _, _, _, _, btnY := paletteGeometry()
+	bw := (OptionsW - 16) / 4
+	return []uiButton{
+		{x0 + 8, btnY, bw - 4, 24, "Dodaj", g.paletteAdd},
+		{x0 + 8 + bw, btnY, bw - 4, 24, "Usuń", g.paletteRemove},
+		{x0 + 8 + 2*bw, btnY, bw - 4, 24, "<", func() { g.paletteMove(-1) }},
+		{x0 + 8 + 3*bw, btnY, bw - 4, 24, ">", func() { g.paletteMove(1) }},
+		{x0 + 8, btnY + 28, 2*bw - 4, 24, "Wczytaj paletę", g.paletteLoad},
+		{x0 + 8 + 2*bw, btnY + 28, 2*bw - 4, 24, "Zapisz paletę", g.paletteSave},
+	}
+}
+
+// selectColor wybiera kolor palety do edycji i do rysowania
+func (g *Game) selectColor(i int) {
+	if i < 0 || i >= len(palette) {
+		return
+	}
+	g.palSel = i
+	g.monoColor = i
+	if len(palette) > 1 {
+		g.colorSliderValue = float64(i) / float64(len(palette)-1)
+	}
+	h, s, v := rgbToHSV(palette[i])
+	g.palHSV = [3]float64{h, s, v}
+	g.pickHSV = g.palHSV
+}
+
+// reselectColor - wybór koloru po wymianie palety: indeks przycięty do
+// nowej palety, kolor 24-bit rysowania (TrueColorFlag) zostaje bez zmian
+func (g *Game) reselectColor() {
+	mono, i := g.monoColor, g.palSel
+	if !isTrueColor(mono) {
+		i = mono
+	}
+	g.selectColor(clampInt(i, 0, len(palette)-1))
+	if isTrueColor(mono) {
+		g.monoColor = mono
+	}
+}
+
+// remapCells zmienia indeksy palety w siatce, we wszystkich znakach,
+// w klatkach animacji i w historii cofania (kolory 24-bit zostają bez zmian)
+func (g *Game) remapCells(fn func(v int) int) {
+	f := func(v int) int {
+		if isTrueColor(v) {
+			return v
+		}
+		return fn(v)
+	}
+	remap := func(gl *Glyph) { // także ukryte komórki poza siatką (glyphsize.go)
+		for y := 0; y < MaxGridH; y++ {
+			for x := 0; x < MaxGridW; x++ {
+				gl[y][x] = f(gl[y][x])
+			}
+		}
+	}
+	remap((*Glyph)(&g.cells))
+	for i := range g.glyphs {
+		remap(&g.glyphs[i])
+	}
+	for i := range g.sprite.Frames {
+		remap(&g.sprite.Frames[i].Cells)
+	}
+	remap(&g.spriteSaved)
+	g.remapHistory(remap)
+	g.resetEffect()
+	g.updateDisplayGlyphs()
+}
+
+func (g *Game) paletteAdd() {
+	if len(palette) >= MaxPalette {
+		g.lastExport = "Paleta pełna"
+		return
+	}
+	// nowy kolor = kopia wybranego, wstawiony za nim
+	i := g.palSel + 1
+	palette = append(palette[:i], append([]color.RGBA{palette[g.palSel]}, palette[i:]...)...)
+	g.remapCells(func(v int) int {
+		if v >= i {
+			return v + 1
+		}
+		return v
+	})
+	g.selectColor(i)
+	g.updatePreviewText()
+}
+
+func (g *Game) paletteRemove() {
+	i := g.palSel
+	if i == 0 || len(palette) <= 2 {
+		return
+	}
+	palette = append(palette[:i], palette[i+1:]...)
+	// piksele w usuniętym kolorze gasną
+	g.remapCells(func(v int) int {
+		switch {
+		case v == i:
+			return 0
+		case v > i:
+			return v - 1
+		}
+		return v
+	})
+	g.selectColor(clampInt(i, 1, len(palette)-1))
+	g.updatePreviewText()
+}
+
+// paletteMove przesuwa wybrany kolor o d pozycji (rysunki zachowują kolory)
+func (g *Game) paletteMove(d int) {
+	i, j := g.palSel, g.palSel+d
+	if i == 0 || j < 1 || j >= len(palette) {
+		return
+	}
+	palette[i], palette[j] = palette[j], palette[i]
+	g.remapCells(func(v int) int {
+		switch v {
+		case i:
+			return j
+		case j:
+			return i
+		}
+		return v
+	})
+	g.selectColor(j)
+	g.updatePreviewText()
+}
+
+func (g *Game) paletteLoad() {
+	path, err := chooseOpenFilename("Wczytaj paletę", "Palety (gpl, txt, json)", "gpl", "txt", "json")
+	if err != nil {
+		if !errors.Is(err, dialog.ErrCancelled) {
+			g.lastExport = "Błąd wyboru pliku"
+		}
+		return
+	}
+	pal, err := loadPaletteFile(path)
+	if err != nil {
+		g.lastExport = "Błąd palety: " + err.Error()
+		return
+	}
+	// rysunki zachowują wygląd: każdy stary kolor -> najbliższy w nowej
+	// palecie (OFF zostaje OFF, zapalony piksel nie gaśnie)
+	old := palette
+	palette = pal
+	g.remapCells(func(v int) int {
+		if v <= 0 || v >= len(old) || len(pal) < 2 {
+			return 0
+		}
+		return nearestColor(old[v], pal[1:]) + 1
+	})
+	rows := (len(palette) + palPerRow - 1) / palPerRow
+	g.palScroll = clampInt(g.palScroll, 0, max(rows-palRows, 0))
+	g.reselectColor()
+	g.lastExport = path
+	g.updatePreviewText()
+}
+
+func (g *Game) paletteSave() {
+	path, err := chooseFilename("paleta", "gpl")
+	if err != nil {
+		return
+	}
+	if filepath.Ext(path) == "" {
+		path += ".gpl"
+	}
+	if err := savePaletteFile(path, palette); err != nil {
+		g.lastExport = "Błąd zapisu palety"
+		return
+	}
+	g.lastExport = path
+}
+
+// setSliderValue ustawia wartość suwaka edycji (f = 0..1)
+func (g *Game) setSliderValue(s int, f float64) {
+	i := g.palSel
+	if i == 0 {
+		return // OFF zostaje czarny
+	}
+	f = clampFloat(f, 0, 1)
+	c := palette[i]
+	switch s {
+	case palSliderR:
+		c.R = uint8(f * 255)
+	case palSliderG:
+		c.G = uint8(f * 255)
+	case palSliderB:
+		c.B = uint8(f * 255)
+	case palSliderH:
+		g.palHSV[0] = f * 359
+	case palSliderS:
+		g.palHSV[1] = f
+	case palSliderV:
+		g.palHSV[2] = f
+	}
+
+	if s >= palSliderH {
+		c = hsvToRGB(g.palHSV[0], g.palHSV[1], g.palHSV[2])
+	} else {
+		h, sat, v := rgbToHSV(c)
+		g.palHSV = [3]float64{h, sat, v}
+	}
+	palette[i] = c
+	g.updatePreviewText()
+}
+
+// sliderFraction zwraca wartość suwaka s wybranego koloru (0..1)
+func (g *Game) sliderFraction(s int) float64 {
+	c := palette[g.palSel]
+	switch s {
+	case palSliderR:
+		return float64(c.R) / 255
+	case palSliderG:
+		return float64(c.G) / 255
+	case palSliderB:
+		return float64(c.B) / 255
+	case palSliderH:
+		return g.palHSV[0] / 359
+	case palSliderS:
+		return g.palHSV[1]
+	}
+	return g.palHSV[2]
+}
+
+func (g *Game) sliderText(s int) string {
+	f := g.sliderFraction(s)
+	switch s {
+	case palSliderH:
+		return fmt.Sprintf("%s %3.0f", palSliderNames[s], f*359)
+	case palSliderS, palSliderV:
+		return fmt.Sprintf("%s %3.0f", palSliderNames[s], f*100)
+	}
+	return fmt.Sprintf("%s %3.0f", palSliderNames[s], f*255)
+}
+
+// handlePaletteClick - kliknięcie w zakładce palety
+func (g *Game) handlePaletteClick(x, y, dir int) {
+	if dir < 0 {
+		return
+	}
+	swX, swY, _, sliderX, sliderY, sliderW, _ := paletteGeometry()
+
+	// próbki
+	if x >= swX && x < swX+palPerRow*palSwatch && y >= swY && y < swY+palRows*palSwatch {
+		i := (g.palScroll+(y-swY)/palSwatch)*palPerRow + (x-swX)/palSwatch
+		g.selectColor(i)
+		return
+	}
+
+	// suwaki
+	if x >= sliderX-4 && x <= sliderX+sliderW+4 && y >= sliderY && y < sliderY+palSliderCount*palSliderRow {
+		g.palDrag = (y - sliderY) / palSliderRow
+		g.dragPaletteSlider(x)
+		return
+	}
+
+	clickButtons(g.paletteButtons(), x, y)
+}
+
+// dragPaletteSlider - przeciąganie chwyconego suwaka
+func (g *Game) dragPaletteSlider(x int) {
+	_, _, _, sliderX, _, sliderW, _ := paletteGeometry()
+	g.setSliderValue(g.palDrag, float64(x-sliderX)/float64(sliderW))
+}
+
+// scrollPalette przewija próbki kółkiem myszy
+func (g *Game) scrollPalette(wy float64) {
+	rows := (len(palette) + palPerRow - 1) / palPerRow
+	g.palScroll = clampInt(g.palScroll-int(wy), 0, max(rows-palRows, 0))
+}
+
+// drawPaletteEditor rysuje zakładkę palety
+func (g *Game) drawPaletteEditor(screen *ebiten.Image) {
+	x0, y0, _, _ := sideRect()
+	swX, swY, infoY, sliderX, sliderY, sliderW, _ := paletteGeometry()
+
+	drawText(screen, fmt.Sprintf("Paleta (%d kolorów)", len(palette)), x0+8, y0+20)
+
+	// próbki
+	for r := 0; r < palRows; r++ {
+		for c := 0; c < palPerRow; c++ {
+			i := (g.palScroll+r)*palPerRow + c
+			if i >= len(palette) {
+				break
+			}
+			px := swX + c*palSwatch
+			py := swY + r*palSwatch
+			if i == g.palSel {
+				fillRect(screen, px, py, palSwatch, palSwatch, color.White)
+			}
+			fillRect(screen, px+2, py+2, palSwatch-4, palSwatch-4, palette[i])
+		}
+	}
+
+	// wybrany kolor
+	c := palette[g.palSel]
+	info := fmt.Sprintf("Kolor %d: %s", g.palSel, hexColor(c))
+	if g.palSel == 0 {
+		info += " (OFF)"
+	}
+	drawText(screen, info, x0+8, infoY)
+
+	// suwaki
+	for s := 0; s < palSliderCount; s++ {
+		sy := sliderY + s*palSliderRow
+		ebitenutil.DebugPrintAt(screen, g.sliderText(s), x0+8, sy)
+		fillRect(screen, sliderX, sy+3, sliderW, 10, color.RGBA{R: 0x30, G: 0x30, B: 0x36, A: 0xff})
+		hx := sliderX + int(g.sliderFraction(s)*float64(sliderW))
+		fillRect(screen, hx-3, sy+1, 6, 14, c)
+		fillRect(screen, hx-1, sy+1, 2, 14, color.White)
+	}
+
+	for _, b := range g.paletteButtons() {
+		b.draw(screen, btnColor)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestParseGPL - paleta GIMP: nagłówek i komentarze pomijane
+func TestParseGPL(t *testing.T) {
+	data := "GIMP Palette\nName: test\nColumns: 4\n#\n" +
+		"  0   0   0\tIndex 0\n" +
+		"255 128   1 pomarańcz\r\n" +
+		"\n" +
+		"# komentarz\n" +
+		" 16  32  64\n"
+	pal, err := parseGPL([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []color.RGBA{rgb(0, 0, 0), rgb(255, 128, 1), rgb(16, 32, 64)}
+	if !equalColors(pal, want) {
+		t.Errorf("parseGPL = %v, want %v", pal, want)
+	}
+
+	for _, bad := range []string{"12 34\n", "256 0 0\n", "-1 0 0\n", "a b c\n"} {
+		if _, err := parseGPL([]byte(bad)); err == nil {
+			t.Errorf("parseGPL(%q): brak błędu", bad)
+		}
+	}
+}
+
+// TestParsePaintNET - paleta Paint.NET: AARRGGBB, komentarze od ';'
+func TestParsePaintNET(t *testing.T) {
+	data := ";paint.net Palette File\n; komentarz\nFF000000\r\nff10ff80\n\n80123456\n"
+	pal, err := parsePaintNET([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// kanał alfa jest pomijany
+	want := []color.RGBA{rgb(0, 0, 0), rgb(0x10, 0xff, 0x80), rgb(0x12, 0x34, 0x56)}
+	if !equalColors(pal, want) {
+		t.Errorf("parsePaintNET = %v, want %v", pal, want)
+	}
+
+	for _, bad := range []string{"FF00\n", "FFGG0000\n", "FF0000000\n"} {
+		if _, err := parsePaintNET([]byte(bad)); err == nil {
+			t.Errorf("parsePaintNET(%q): brak błędu", bad)
+		}
+	}
+}
+
+func equalColors(a, b []color.RGBA) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
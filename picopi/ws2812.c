@@ -0,0 +1,63 @@
+#include "pico/stdlib.h"
+#include "hardware/pio.h"
+#include "ws2812.pio.h"
+
+#define PIN_WS2812 2
+#define MAX_LEDS   1024
+
+// ramka: 0xA5 0x5A, długość (2 bajty, starszy pierwszy), 3 bajty na diodę
+#define SYNC0 0xA5
+#define SYNC1 0x5A
+
+static uint8_t leds[MAX_LEDS * 3];
+
+static int read_byte()
+{
+	int c;
+	do {
+		c = getchar_timeout_us(1000); // 1ms timeout
+	} while (c < 0);
+	return c;
+}
+
+// -------- main --------
+int main()
+{
+	stdio_init_all(); // USB CDC
+	sleep_ms(500); // poczekaj na inicjalizację USB
+
+	while (true) {
+		int c = getchar_timeout_us(0);
+		if (c == 0xAA) {
+			putchar_raw(0x57); // odpowiedź handshake: firmware WS2812
+			break;
+		}
+	}
+
+	PIO pio = pio0;
+	uint sm = 0;
+	uint offset = pio_add_program(pio, &ws2812_program);
+	ws2812_program_init(pio, sm, offset, PIN_WS2812, 800000);
+
+	while (true) {
+		// synchronizacja na nagłówku ramki
+		if (read_byte() != SYNC0 || read_byte() != SYNC1) {
+			continue;
+		}
+		int len = read_byte() << 8;
+		len |= read_byte();
+		if (len > (int)sizeof(leds) || len % 3 != 0) {
+			continue;
+		}
+		for (int i = 0; i < len; i++) {
+			leds[i] = (uint8_t)read_byte();
+		}
+
+		// bajty już w kolejności diod (GRB / RGB) i po kalibracji
+		for (int i = 0; i < len; i += 3) {
+			uint32_t v = (uint32_t)leds[i] << 24 | (uint32_t)leds[i + 1] << 16 | (uint32_t)leds[i + 2] << 8;
+			pio_sm_put_blocking(pio, sm, v);
+		}
+		sleep_us(300); // reset łańcucha
+	}
+}
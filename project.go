@@ -0,0 +1,281 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: project.go
+
+Zapis i odczyt projektu czcionki (JSON): znaki razem z paletą,
+bo różne typy diod potrzebują różnych zestawów kolorów, oraz animacja
+z klatek (sprite.go).
+Zakładka "Projekt" panelu bocznego, skróty Ctrl+S / Ctrl+O.
+
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/sqweek/dialog"
+)
+
+// wersja formatu pliku projektu (2: rozmiar znaku, 3: animacja,
+// 4: kod pierwszego znaku)
+const projectVersion = 4
+
+// projectFile - zawartość pliku projektu
+type projectFile struct {
+	Version int      `json:"version"`
+	Width   int      `json:"width,omitempty"` // rozmiar znaku, brak = 8x8
+	Height  int      `json:"height,omitempty"`
+	Palette []string `json:"palette"` // "#RRGGBB", pozycja 0 = OFF
+	Glyphs  []Glyph  `json:"glyphs"`
+
+	FirstCode *int `json:"first_code,omitempty"` // brak = DefaultFirstCode
+
+	// czcionka proporcjonalna
+	Proportional bool  `json:"proportional,omitempty"`
+	Spacing      *int  `json:"spacing,omitempty"` // brak = DefaultLetterSpacing
+	Advance      []int `json:"advance,omitempty"` // 0 = szerokość automatyczna
+
+	Metrics *fontMetrics `json:"metrics,omitempty"`
+	Kerning []kernPair   `json:"kerning,omitempty"`
+
+	Sprite *spriteDoc `json:"sprite,omitempty"`
+}
+
+// saveProject zapisuje znaki i paletę do pliku JSON
+func (g *Game) saveProject(path string) error {
+	g.syncFrame()
+	m := g.metrics()
+	spacing, firstCode := g.letterSpacing, g.firstCode
+	pf := projectFile{
+		Version: projectVersion,
+		Width:   GridW,
+		Height:  GridH,
+		Palette: make([]string, len(palette)),
+		Glyphs:  g.glyphs,
+
+		FirstCode: &firstCode,
+
+		Proportional: g.proportional,
+		Spacing:      &spacing,
+		Advance:      g.advSlice(),
+
+		Metrics: &m,
+		Kerning: g.kerning,
+		Sprite:  &g.sprite,
+	}
+	for i, c := range palette {
+		pf.Palette[i] = hexColor(c)
+	}
+
+	data, err := json.MarshalIndent(pf, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	g.projectPath = path
+	return nil
+}
+
+// loadProject wczytuje projekt z pliku JSON
+func (g *Game) loadProject(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var pf projectFile
+	if err := json.Unmarshal(data, &pf); err != nil {
+		return err
+	}
+	pal, err := pf.validate()
+	if err != nil {
+		return err
+	}
+
+	// od tego miejsca plik jest poprawny - zmieniamy stan edytora
+	if len(pal) > 0 {
+		palette = pal
+	}
+	g.closeSprite()
+	g.glyphs = pf.Glyphs
+	g.glyphAdv = pf.Advance
+	g.proportional = pf.Proportional
+	g.setGlyphSize(pf.Width, pf.Height)
+	g.letterSpacing = DefaultLetterSpacing // starsze projekty bez pola
+	if pf.Spacing != nil {
+		g.letterSpacing = clampInt(*pf.Spacing, 0, GridW)
+	}
+	g.firstCode = DefaultFirstCode
+	if pf.FirstCode != nil {
+		g.firstCode = clampInt(*pf.FirstCode, 0, 0xFFFF)
+	}
+	g.kerning = normalizeKerning(pf.Kerning)
+	if pf.Sprite != nil {
+		g.sprite = *pf.Sprite
+		g.sprite.normalize()
+	} else {
+		g.sprite = newSpriteDoc()
+	}
+	g.spriteCur, g.spritePlaying, g.spriteScroll = 0, false, 0
+	if m := pf.Metrics; m != nil {
+		g.baseline, g.ascent, g.descent = m.Baseline, m.Ascent, m.Descent
+		g.clampMetrics()
+	} else {
+		g.defaultMetrics()
+	}
+	g.resetHistory()
+	g.activeGlyph = len(g.glyphs)
+	g.glyphIndex = len(g.glyphs)
+	g.setGlyphScroll(0)
+	g.clear()
+	g.updateDisplayGlyphs()
+	g.reselectColor()
+	g.updatePreviewText()
+	g.projectPath = path
+	return nil
+}
+
+// validate sprawdza plik przed zmianą stanu edytora: wersję, rozmiar
+// znaku, paletę i wartości komórek znaków oraz klatek; zwraca paletę
+func (pf *projectFile) validate() ([]color.RGBA, error) {
+	if pf.Version > projectVersion {
+		return nil, fmt.Errorf("projekt w nowszej wersji (%d)", pf.Version)
+	}
+	if pf.Width == 0 || pf.Height == 0 {
+		pf.Width, pf.Height = 8, 8
+	}
+	if pf.Width > MaxGridW || pf.Height > MaxGridH {
+		return nil, fmt.Errorf("za duży znak %dx%d", pf.Width, pf.Height)
+	}
+
+	pal := make([]color.RGBA, 0, len(pf.Palette))
+	for _, s := range pf.Palette {
+		c, err := parseHexColor(s)
+		if err != nil {
+			return nil, err
+		}
+		pal = append(pal, c)
+	}
+	if len(pal) > MaxPalette {
+		return nil, fmt.Errorf("za duża paleta (%d kolorów)", len(pal))
+	}
+
+	// indeksy palety w zakresie, kolory 24-bit bez dodatkowych bitów
+	n := len(pal)
+	if n == 0 {
+		n = len(palette)
+	}
+	check := func(what string, i int, gl Glyph) error {
+		for y := range gl {
+			for x, v := range gl[y] {
+				ok := v >= 0 && v < n || isTrueColor(v) && v <= TrueColorFlag|0xFFFFFF
+				if !ok {
+					return fmt.Errorf("%s %d: nieprawidłowy kolor %d (%d,%d)", what, i, v, x, y)
+				}
+			}
+		}
+		return nil
+	}
+	for i, gl := range pf.Glyphs {
+		if err := check("znak", i, gl); err != nil {
+			return nil, err
+		}
+	}
+	if pf.Sprite != nil {
+		for i, f := range pf.Sprite.Frames {
+			if err := check("klatka", i, f.Cells); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return pal, nil
+}
+
+// saveProjectDialog - zapis z wyborem pliku
+func (g *Game) saveProjectDialog() {
+	path, err := chooseFilename("projekt", "json")
+	if err != nil {
+		return
+	}
+	if filepath.Ext(path) == "" {
+		path += ".json"
+	}
+	if err := g.saveProject(path); err != nil {
+		g.lastExport = "Błąd zapisu projektu"
+		return
+	}
+	g.lastExport = path
+}
+
+// loadProjectDialog - odczyt z wyborem pliku
+func (g *Game) loadProjectDialog() {
+	path, err := chooseOpenFilename("Wczytaj projekt", "Projekt Sun8x8 (json)", "json")
+	if err != nil {
+		if !errors.Is(err, dialog.ErrCancelled) {
+			g.lastExport = "Błąd wyboru pliku"
+		}
+		return
+	}
+	if err := g.loadProject(path); err != nil {
+		g.lastExport = "Błąd projektu: " + err.Error()
+		return
+	}
+	g.lastExport = path
+}
+
+// projectButtons - przyciski zakładki projektu (zapis, odczyt, rozmiar znaku)
+func (g *Game) projectButtons() []uiButton {
+	x0, y0, _, _ := sideRect()
+	buttons := []uiButton{
+		{x0 + 8, y0 + 30, OptionsW - 16, 28, "Zapisz projekt (Ctrl+S)", g.saveProjectDialog},
+		{x0 + 8, y0 + 64, OptionsW - 16, 28, "Wczytaj projekt (Ctrl+O)", g.loadProjectDialog},
+	}
+	bw := (OptionsW - 16) / len(glyphSizePresets)
+	for i, p := range glyphSizePresets {
+		buttons = append(buttons, uiButton{
+			x0 + 8 + i*bw, y0 + 190, bw - 4, 24,
+			fmt.Sprintf("%dx%d", p[0], p[1]), func() { g.setGlyphSize(p[0], p[1]) },
+		})
+	}
+	return buttons
+}
+
+// drawProjectTab rysuje zakładkę projektu
+func (g *Game) drawProjectTab(screen *ebiten.Image) {
+	x0, y0, _, _ := sideRect()
+	drawText(screen, "Projekt czcionki", x0+8, y0+20)
+
+	for _, b := range g.projectButtons() {
+		col := color.Color(btnColor)
+		if b.label == fmt.Sprintf("%dx%d", GridW, GridH) {
+			col = btnColorAct
+		}
+		b.draw(screen, col)
+	}
+	drawText(screen, "Rozmiar znaku (szer. x wys.)", x0+8, y0+182)
+
+	name := "(nie zapisany)"
+	if g.projectPath != "" {
+		name = filepath.Base(g.projectPath)
+	}
+	yy := y0 + 118
+	for _, ln := range []string{
+		"Plik: " + name,
+		fmt.Sprintf("Znaki: %d (%dx%d)", len(g.glyphs), GridW, GridH),
+		fmt.Sprintf("Paleta: %d kolorów", len(palette)),
+	} {
+		drawText(screen, ln, x0+8, yy)
+		yy += 22
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: proportional.go
+
+Czcionka proporcjonalna ("Czcionka proporcjonalna" w "Opcjach"):
+- szerokość znaku (advance) liczona z najdalszej zapalonej kolumny,
+  pusty znak (spacja) ma połowę siatki; w zakładce "Znak" można ją
+  ustawić ręcznie (-/+) lub wrócić do automatycznej
+- wspólny odstęp między znakami ("Odstęp między znakami")
+- eksport C dopisuje tabelę font_widths[] i FONT_SPACING
+- podgląd animacji i matryce przez serial układają znaki wg szerokości
+
+Ręczne szerokości (g.glyphAdv, 0 = auto) są równoległe do g.glyphs.
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// domyślny odstęp między znakami (kolumny)
+const DefaultLetterSpacing = 1
+
+// autoAdvance - szerokość znaku z najdalszej zapalonej kolumny
+func autoAdvance(gl Glyph) int {
+	_, _, maxX, _, ok := glyphBounds(gl)
+	if !ok {
+		return (GridW + 1) / 2 // pusty znak, np. spacja
+	}
+	return maxX + 1
+}
+
+// advanceOverride - ręczna szerokość znaku i (0 = auto)
+func (g *Game) advanceOverride(i int) int {
+	if i < 0 || i >= len(g.glyphAdv) {
+		return 0
+	}
+	return g.glyphAdv[i]
+}
+
+// glyphAdvance - szerokość znaku i o zawartości gl (ręczna lub automatyczna)
+func (g *Game) glyphAdvance(i int, gl Glyph) int {
+	if v := g.advanceOverride(i); v > 0 {
+		return min(v, GridW)
+	}
+	return autoAdvance(gl)
+}
+
+// activeAdvance - szerokość znaku na siatce
+func (g *Game) activeAdvance() int {
+	return g.glyphAdvance(g.activeGlyph, g.glyphCells())
+}
+
+// advances - szerokości wszystkich zapisanych znaków (tabela eksportu)
+func (g *Game) advances() []int {
+	out := make([]int, len(g.glyphs))
+	for i, gl := range g.glyphs {
+		out[i] = g.glyphAdvance(i, gl)
+	}
+	return out
+}
+
+// advSlice - kopia g.glyphAdv o długości len(g.glyphs) (do operacji na liście)
+func (g *Game) advSlice() []int {
+	adv := make([]int, len(g.glyphs))
+	copy(adv, g.glyphAdv)
+	return adv
+}
+
+// setAdvance ustawia ręczną szerokość aktywnego znaku (0 = auto)
+func (g *Game) setAdvance(v int) {
+	if g.fontListLocked() {
+		return
+	}
+	if g.isNewGlyph() {
+		g.lastExport = "Zapisz znak, aby ustawić szerokość"
+		return
+	}
+	v = clampInt(v, 0, GridW)
+	if v == g.advanceOverride(g.activeGlyph) {
+		return
+	}
+	g.pushListUndo()
+	adv := g.advSlice()
+	adv[g.activeGlyph] = v
+	g.glyphAdv = adv
+	g.updatePreviewText()
+}
+
+// layoutAdvance - szerokość znaku w układzie tekstu: GridW albo proporcjonalna
+func (g *Game) layoutAdvance(i int, gl Glyph) int {
+	if !g.proportional {
+		return GridW
+	}
+	return g.glyphAdvance(i, gl)
+}
+
+// layoutSpacing - odstęp za każdym znakiem w układzie tekstu
+func (g *Game) layoutSpacing() int {
+	if !g.proportional {
+		return 0
+	}
+	return g.letterSpacing
+}
+
+// layoutKern - korekta kerningu między znakami o indeksach a i b (metrics.go)
+func (g *Game) layoutKern(a, b int) int {
+	if !g.proportional {
+		return 0
+	}
+	return g.kernAdjust(g.glyphCode(a), g.glyphCode(b))
+}
+
+// layoutGlyphs układa znaki jeden za drugim; idx to indeksy znaków
+// (ręczne szerokości, kerning), zwraca GridH wierszy pikseli
+func (g *Game) layoutGlyphs(glyphs []Glyph, idx []int) [][]int {
+	width := 0
+	pos := make([]int, len(glyphs))
+	advs := make([]int, len(glyphs))
+	ox := 0
+	for k, gl := range glyphs {
+		if k > 0 {
+			ox = max(0, ox+g.layoutKern(idx[k-1], idx[k]))
+		}
+		pos[k] = ox
+		advs[k] = g.layoutAdvance(idx[k], gl)
+		ox += advs[k] + g.layoutSpacing()
+		width = max(width, ox)
+	}
+
+	rows := make([][]int, GridH)
+	for y := range rows {
+		rows[y] = make([]int, width)
+	}
+	for k, gl := range glyphs {
+		for y := 0; y < GridH; y++ {
+			for x := 0; x < advs[k]; x++ {
+				if v := gl[y][x]; v != 0 { // ujemny kerning - znaki mogą na siebie zachodzić
+					rows[y][pos[k]+x] = v
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// displayStrip - edytowany znak i znaki z g.displayGlyphs ułożone w jeden pasek
+func (g *Game) displayStrip() [][]int {
+	glyphs := append([]Glyph{g.glyphCells()}, g.displayGlyphs...)
+	idx := append([]int{g.activeGlyph}, g.displayIdx...)
+	return g.layoutGlyphs(glyphs, idx)
+}
+
+// writeWidths dopisuje tabelę szerokości znaków (czcionka proporcjonalna)
+func writeWidths(b *strings.Builder, opts ExportOptions) {
+	if opts.Widths == nil {
+		return
+	}
+	pm := ""
+	if opts.Progmem {
+		pm = " PROGMEM"
+	}
+	b.WriteString("\n// czcionka proporcjonalna: szerokość znaku (kolumny) + odstęp\n")
+	b.WriteString(fmt.Sprintf("#define FONT_SPACING %d\n", opts.Spacing))
+	b.WriteString(fmt.Sprintf("const uint8_t font_widths[%d]%s = {\n", len(opts.Widths), pm))
+	for i, w := range opts.Widths {
+		if i%16 == 0 {
+			b.WriteString("  ")
+		}
+		b.WriteString(fmt.Sprintf("%d,", w))
+		if i%16 == 15 || i == len(opts.Widths)-1 {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	b.WriteString("};\n")
+}
+
+// advanceButtons - szerokość znaku w zakładce "Znak"
+func (g *Game) advanceButtons(x0, y0 int) []uiButton {
+	return []uiButton{
+		{x0 + 150, y0, 34, 24, "-", func() { g.setAdvance(max(1, g.activeAdvance()-1)) }},
+		{x0 + 188, y0, 34, 24, "+", func() { g.setAdvance(g.activeAdvance() + 1) }},
+		{x0 + 226, y0, 66, 24, "Auto", func() { g.setAdvance(0) }},
+	}
+}
+
+// advanceLabel - opis szerokości aktywnego znaku
+func (g *Game) advanceLabel() string {
+	mode := "auto"
+	if g.advanceOverride(g.activeGlyph) > 0 {
+		mode = "ręczna"
+	}
+	return fmt.Sprintf("Szerokość: %d (%s)", g.activeAdvance(), mode)
+}
@@ -4,11 +4,19 @@ Autor: SunRiver / Lothar TeaM
   Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
  Plik: serial.go
 
+Wysyłka ramek do Pico - format zależy od firmware (serial_detect.go):
+- MAX7219 (picopi/8x8.c): surowe ramki po 32 bajty (4 matryce 8x8)
+- WS2812 (picopi/ws2812.c): ramka = 0xA5 0x5A, długość (2 bajty, starszy
+  pierwszy), strumień diod (3 bajty na diodę, kolejność fizyczna)
+Strumienia WS2812 nie wysyłamy do firmware MAX7219 - czytałby go jako
+kolejne ramki matryc.
+
 */
 
 package main
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/tarm/serial"
@@ -17,11 +25,17 @@ import (
 type SerialMatrix struct {
 	port     *serial.Port
 	portName string
+	kind     byte // rodzaj firmware: PicoMAX7219 / PicoWS2812
 	width    int
 	height   int
+
+	lastWS2812 []byte // ostatnio wysłany strumień WS2812
 }
 
-func NewSerialMatrix(portName string, width, height int) *SerialMatrix {
+// nagłówek ramki WS2812 (picopi/ws2812.c)
+var ws2812Sync = [2]byte{0xA5, 0x5A}
+
+func NewSerialMatrix(portName string, kind byte, width, height int) *SerialMatrix {
 	if portName == "" {
 		fmt.Println("Serial: brak portu")
 		return nil
@@ -40,11 +54,17 @@ func NewSerialMatrix(portName string, width, height int) *SerialMatrix {
 
 	return &SerialMatrix{
 		port:   c,
+		kind:   kind,
 		width:  width,
 		height: height,
 	}
 }
 
+// WS2812 - na Pico działa firmware łańcucha WS2812
+func (s *SerialMatrix) WS2812() bool {
+	return s.kind == PicoWS2812
+}
+
 func (s *SerialMatrix) Close() {
 	if s.port != nil {
 		if err := s.port.Close(); err != nil {
@@ -54,17 +74,17 @@ func (s *SerialMatrix) Close() {
 	}
 }
 
-// SendFrame - konwersja 8x8 (lub NxM) komórek na bajty
+// SendFrame - konwersja komórek NxM na bajty w kolejności firmware
+// (picopi/8x8.c): kolejne matryce po 8 kolumn, w każdej wszystkie wiersze
+// (bit 7 = lewa kolumna matrycy)
 func (s *SerialMatrix) SendFrame(cells [][]int) error {
-	frame := make([]byte, s.height)
+	frame := make([]byte, (s.width+7)/8*s.height)
 	for y := 0; y < s.height; y++ {
-		var b byte
 		for x := 0; x < s.width; x++ {
 			if cells[y][x] != 0 {
-				b |= 1 << (7 - x)
+				frame[x/8*s.height+y] |= 1 << uint(7-x%8)
 			}
 		}
-		frame[y] = b
 	}
 
 	if s.port != nil {
@@ -76,3 +96,24 @@ func (s *SerialMatrix) SendFrame(cells [][]int) error {
 	}
 	return nil
 }
+
+// SendWS2812 wysyła strumień bajtów WS2812 (3 bajty na diodę, kolejność fizyczna)
+// w ramce z nagłówkiem i długością; tylko do firmware WS2812.
+// Ramka wysyłana jest tylko gdy się zmieniła - 115200 bodów nie wystarcza
+// na kilkaset bajtów w każdej klatce.
+func (s *SerialMatrix) SendWS2812(stream []byte) error {
+	if !s.WS2812() || bytes.Equal(stream, s.lastWS2812) {
+		return nil
+	}
+
+	if s.port != nil {
+		frame := append(ws2812Sync[:], byte(len(stream)>>8), byte(len(stream)))
+		_, err := s.port.Write(append(frame, stream...))
+		if err != nil {
+			fmt.Println("Błąd wysyłki WS2812:", err)
+			return err
+		}
+	}
+	s.lastWS2812 = append(s.lastWS2812[:0], stream...)
+	return nil
+}
@@ -9,8 +9,11 @@ podłączonego przez port szeregowy (COM).
 
 Działanie:
 - Funkcja `detectSerialPort` skanuje porty COM od 3 do 40 i sprawdza, czy jest podłączone Pico.
-- Funkcja `isPicoOnPort` wykonuje handshake wysyłając bajt 0xAA i oczekując odpowiedzi 0x55.
-- Po wykryciu urządzenia zwracany jest numer portu COM, na którym jest Pico.
+- Funkcja `picoOnPort` wykonuje handshake wysyłając bajt 0xAA; odpowiedź
+  mówi, jaki firmware działa na Pico: 0x55 - matryce MAX7219 (picopi/8x8.c),
+  0x57 - łańcuch WS2812 (picopi/ws2812.c).
+- Po wykryciu urządzenia zwracany jest numer portu COM, na którym jest Pico,
+  i rodzaj firmware.
 
 */
 
@@ -24,8 +27,15 @@ import (
 	"github.com/tarm/serial"
 )
 
-// Wykrywanie pico na porcie --  (0xAA) odpowiedź (0x55)
-func isPicoOnPort(port string) bool {
+// odpowiedzi na handshake - rodzaj firmware Pico
+const (
+	PicoMAX7219 = 0x55 // picopi/8x8.c - ramki 32 bajty
+	PicoWS2812  = 0x57 // picopi/ws2812.c - ramki z nagłówkiem (serial.go)
+)
+
+// Wykrywanie pico na porcie --  (0xAA) odpowiedź (0x55 / 0x57);
+// zwraca rodzaj firmware, 0 = brak Pico
+func picoOnPort(port string) byte {
 
 	c := &serial.Config{
 		Name:        port,
@@ -35,7 +45,7 @@ func isPicoOnPort(port string) bool {
 
 	p, err := serial.OpenPort(c)
 	if err != nil {
-		return false
+		return 0
 	}
 	defer func() {
 		if err := p.Close(); err != nil {
@@ -45,27 +55,39 @@ func isPicoOnPort(port string) bool {
 
 	// handshake
 	if _, err := p.Write([]byte{0xAA}); err != nil {
-		return false
+		return 0
 	}
 
 	buf := make([]byte, 1)
 	n, err := p.Read(buf)
-	if err != nil {
-		return false
+	if err != nil || n != 1 {
+		return 0
 	}
 
-	return n == 1 && buf[0] == 0x55
+	switch buf[0] {
+	case PicoMAX7219, PicoWS2812:
+		return buf[0]
+	}
+	return 0
 }
 
-func detectSerialPort() string {
+func detectSerialPort() (string, byte) {
 
 	for i := 3; i <= 40; i++ {
 		port := "COM" + strconv.Itoa(i)
 
-		if isPicoOnPort(port) {
-			return port // to jest port piko
+		if kind := picoOnPort(port); kind != 0 {
+			return port, kind // to jest port piko
 		}
 	}
 
-	return ""
+	return "", 0
+}
+
+// picoKindName - opis firmware do paska statusu
+func picoKindName(kind byte) string {
+	if kind == PicoWS2812 {
+		return " (WS2812)"
+	}
+	return " (MAX7219)"
 }
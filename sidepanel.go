@@ -0,0 +1,201 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: sidepanel.go
+
+Panel boczny po prawej stronie okna:
+- pasek zakładek (Opcje, Paleta, Kolor, Znak, Zestaw, Projekt, Metryka, Klatki)
+- treść wybranej zakładki
+- na dole zawsze podgląd wyświetlacza WS2812
+
+*/
+
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// wymiary panelu bocznego
+const (
+	OptionsW = 300
+	TabH     = 24
+	TabW     = 75
+
+	// pod zakładkami: podgląd wyświetlacza WS2812
+	WS2812PreviewH = 220
+)
+
+// zakładki panelu bocznego
+const (
+	TabOptions = iota
+	TabPalette
+	TabColor
+	TabGlyph
+	TabBatch
+	TabProject
+	TabMetrics
+	TabSprite
+
+	tabCount
+)
+
+var tabNames = [tabCount]string{"Opcje", "Paleta", "Kolor", "Znak", "Zestaw", "Projekt", "Metryka", "Klatki"}
+
+// uiButton - prosty przycisk rysowany i klikany w tym samym miejscu
+type uiButton struct {
+	x, y, w, h int
+	label      string
+	action     func()
+}
+
+func (b uiButton) hit(x, y int) bool {
+	return x >= b.x && x <= b.x+b.w && y >= b.y && y <= b.y+b.h
+}
+
+func (b uiButton) draw(screen *ebiten.Image, col color.Color) {
+	fillRect(screen, b.x, b.y, b.w, b.h, col)
+	drawText(screen, b.label, b.x+6, b.y+b.h/2+6)
+}
+
+// kolory przycisków panelu bocznego
+var (
+	btnColor    = color.RGBA{R: 0x30, G: 0x30, B: 0x36, A: 0xff}
+	btnColorAct = color.RGBA{R: 0x2A, G: 0x80, B: 0xFF, A: 0xff}
+)
+
+// clickButtons wywołuje akcję przycisku pod kursorem
+func clickButtons(buttons []uiButton, x, y int) bool {
+	for _, b := range buttons {
+		if b.hit(x, y) {
+			b.action()
+			return true
+		}
+	}
+	return false
+}
+
+func tabRows() int {
+	return (int(tabCount) + OptionsW/TabW - 1) / (OptionsW / TabW)
+}
+
+// sideRect - obszar treści zakładki (pod paskiem zakładek, nad podglądem WS2812)
+func sideRect() (x, y, w, h int) {
+	top := tabRows() * TabH
+	return CanvasW - OptionsW, top, OptionsW, CanvasH - 48 - WS2812PreviewH - top
+}
+
+func isInSide(x, y int) bool {
+	sx, sy, sw, sh := sideRect()
+	return x >= sx && x < sx+sw && y >= sy && y < sy+sh
+}
+
+// tabButtons zwraca przyciski paska zakładek
+func (g *Game) tabButtons() []uiButton {
+	perRow := OptionsW / TabW
+	buttons := make([]uiButton, 0, tabCount)
+	for i := 0; i < int(tabCount); i++ {
+		tab := i
+		buttons = append(buttons, uiButton{
+			x:      CanvasW - OptionsW + (i%perRow)*TabW,
+			y:      (i / perRow) * TabH,
+			w:      TabW - 2,
+			h:      TabH - 2,
+			label:  tabNames[i],
+			action: func() { g.sideTab = tab },
+		})
+	}
+	return buttons
+}
+
+// handleSideClick obsługuje kliknięcie w panelu bocznym (dir = +1 LPM, -1 PPM)
+func (g *Game) handleSideClick(x, y, dir int) bool {
+	if x < CanvasW-OptionsW {
+		return false
+	}
+	if dir > 0 && clickButtons(g.tabButtons(), x, y) {
+		return true
+	}
+	if !isInSide(x, y) {
+		return false
+	}
+
+	switch g.sideTab {
+	case TabOptions:
+		g.handleOptionClick(x, y, dir)
+	case TabPalette:
+		g.handlePaletteClick(x, y, dir)
+	case TabColor:
+		g.handlePickerClick(x, y, dir)
+	case TabGlyph:
+		g.handleTransformClick(x, y, dir)
+	case TabBatch:
+		g.handleBatchClick(x, y, dir)
+	case TabProject:
+		if dir > 0 {
+			clickButtons(g.projectButtons(), x, y)
+		}
+	case TabMetrics:
+		g.handleMetricsClick(x, y, dir)
+	case TabSprite:
+		g.handleSpriteClick(x, y, dir)
+	}
+	return true
+}
+
+// handleSideWheel obsługuje kółko myszy nad panelem bocznym
+func (g *Game) handleSideWheel(x, y int, wy float64) bool {
+	if !isInSide(x, y) {
+		return false
+	}
+	switch g.sideTab {
+	case TabOptions:
+		g.scrollOptions(wy)
+	case TabPalette:
+		g.scrollPalette(wy)
+	case TabMetrics:
+		g.scrollKerning(wy)
+	case TabSprite:
+		g.scrollFrames(wy)
+	}
+	return true
+}
+
+// drawSidePanel rysuje zakładki, treść wybranej zakładki i podgląd WS2812
+func (g *Game) drawSidePanel(screen *ebiten.Image) {
+	x0 := CanvasW - OptionsW
+	fillRect(screen, x0, 0, OptionsW, CanvasH-48, color.RGBA{R: 0x14, G: 0x14, B: 0x18, A: 0xff})
+
+	for i, b := range g.tabButtons() {
+		col := color.Color(btnColor)
+		if i == g.sideTab {
+			col = btnColorAct
+		}
+		b.draw(screen, col)
+	}
+
+	switch g.sideTab {
+	case TabOptions:
+		g.drawOptions(screen)
+	case TabPalette:
+		g.drawPaletteEditor(screen)
+	case TabColor:
+		g.drawColorPicker(screen)
+	case TabGlyph:
+		g.drawTransformTab(screen)
+	case TabBatch:
+		g.drawBatchTab(screen)
+	case TabProject:
+		g.drawProjectTab(screen)
+	case TabMetrics:
+		g.drawMetricsTab(screen)
+	case TabSprite:
+		g.drawSpriteTab(screen)
+	}
+
+	sx, sy, sw, sh := sideRect()
+	g.drawWS2812Preview(screen, sx, sy+sh, sw, WS2812PreviewH)
+}
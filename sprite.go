@@ -0,0 +1,528 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: sprite.go
+
+Animacja z klatek (zakładka "Klatki") - ikony animowane: spinnery,
+serca, pogoda ...:
+- klatki mają rozmiar znaku (domyślnie 8x8) i własny czas wyświetlania
+- tryb "Edycja klatek" (przycisk w zakładce) - siatka edytora edytuje
+  bieżącą klatkę (wszystkie narzędzia, przekształcenia, HEX, zakładki
+  "Kolor" i "Paleta"), tryb trwa po przełączeniu zakładki; każda klatka
+  ma własną historię cofania; lista znaków jest wtedy zablokowana,
+  a PgUp / PgDn, Insert, Delete i Ctrl+D działają na klatkach
+- odtwarzanie: raz, pętla albo ping-pong; opcjonalnie na żywo na matryce
+- eksport C: te same generatory co czcionka (tablica anim[], stałe ANIM_*)
+  plus tabela czasów anim_durations[] w ms
+- animacja zapisuje się razem z projektem
+
+Operacje na klatkach (dodanie, duplikat, usunięcie) nie trafiają do
+historii cofania.
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// czas klatki (ms)
+const (
+	DefaultFrameMs = 100
+	MinFrameMs     = 10
+	MaxFrameMs     = 10000
+	FrameMsStep    = 10
+)
+
+// tryby odtwarzania animacji
+const (
+	PlayOnce = iota
+	PlayLoop
+	PlayPingPong
+
+	playModeCount
+)
+
+var playModeNames = [playModeCount]string{"Raz", "Pętla", "Ping-pong"}
+
+// układ paska miniatur klatek w zakładce
+const (
+	frameThumb = 24 // rozmiar miniatury (px)
+	framePitch = 30
+)
+
+// spriteFrame - jedna klatka animacji
+type spriteFrame struct {
+	Cells    Glyph      `json:"cells"`
+	Duration int        `json:"duration"` // ms
+	hist     *undoStack // historia siatki tej klatki
+}
+
+// spriteDoc - dokument animacji (zapisywany w projekcie)
+type spriteDoc struct {
+	Frames []spriteFrame `json:"frames"`
+	Mode   int           `json:"mode"` // PlayOnce / PlayLoop / PlayPingPong
+}
+
+// newSpriteDoc - animacja z jedną pustą klatką
+func newSpriteDoc() spriteDoc {
+	return spriteDoc{
+		Frames: []spriteFrame{{Duration: DefaultFrameMs}},
+		Mode:   PlayLoop,
+	}
+}
+
+// normalize poprawia dokument wczytany z pliku
+func (d *spriteDoc) normalize() {
+	if len(d.Frames) == 0 {
+		*d = newSpriteDoc()
+		return
+	}
+	for i := range d.Frames {
+		d.Frames[i].Cells = cropGlyph(d.Frames[i].Cells)
+		d.Frames[i].Duration = clampInt(d.Frames[i].Duration, MinFrameMs, MaxFrameMs)
+		d.Frames[i].hist = nil
+	}
+	d.Mode = clampInt(d.Mode, 0, playModeCount-1)
+}
+
+// glyphs - piksele wszystkich klatek (do eksportu)
+func (d *spriteDoc) glyphs() []Glyph {
+	out := make([]Glyph, len(d.Frames))
+	for i, f := range d.Frames {
+		out[i] = f.Cells
+	}
+	return out
+}
+
+// spriteMode - siatka edytuje klatkę animacji (tryb "Edycja klatek")
+func (g *Game) spriteMode() bool {
+	return g.spriteEdit
+}
+
+// setSpriteEdit włącza / wyłącza edycję klatek; zamienia zawartość
+// siatki (znak <-> klatka)
+func (g *Game) setSpriteEdit(on bool) {
+	if on == g.spriteEdit {
+		return
+	}
+	g.stroke.active = false
+	if on {
+		g.spriteSaved = g.cells
+		g.cells = g.sprite.Frames[g.spriteCur].Cells
+	} else {
+		g.syncFrame()
+		g.cells = g.spriteSaved
+	}
+	g.spriteEdit = on
+	g.updatePreviewText()
+}
+
+// closeSprite wychodzi z trybu animacji (np. przed wczytaniem projektu)
+func (g *Game) closeSprite() {
+	g.setSpriteEdit(false)
+}
+
+// glyphCells - edytowany znak (w trybie animacji siatka zawiera klatkę)
+func (g *Game) glyphCells() Glyph {
+	if g.spriteMode() {
+		return g.spriteSaved
+	}
+	return Glyph(g.cells)
+}
+
+// fontListLocked - w trybie animacji operacje na liście znaków są zablokowane
+func (g *Game) fontListLocked() bool {
+	if !g.spriteMode() {
+		return false
+	}
+	g.lastExport = "Tryb animacji: lista znaków zablokowana"
+	return true
+}
+
+// frameHist - historia siatki bieżącej klatki
+func (g *Game) frameHist() *undoStack {
+	f := &g.sprite.Frames[g.spriteCur]
+	if f.hist == nil {
+		f.hist = &undoStack{}
+	}
+	return f.hist
+}
+
+// syncFrame zapisuje siatkę do bieżącej klatki
+func (g *Game) syncFrame() {
+	if g.spriteMode() {
+		g.sprite.Frames[g.spriteCur].Cells = Glyph(g.cells)
+	}
+}
+
+// selectFrame wybiera klatkę i wczytuje ją do siatki
+func (g *Game) selectFrame(i int) {
+	g.syncFrame()
+	g.spriteCur = clampInt(i, 0, len(g.sprite.Frames)-1)
+	g.stroke.active = false
+	if g.spriteMode() {
+		g.cells = g.sprite.Frames[g.spriteCur].Cells
+	}
+	g.scrollToFrame()
+	g.updatePreviewText()
+}
+
+// insertFrame wstawia klatkę f za bieżącą i ją wybiera
+func (g *Game) insertFrame(f spriteFrame) {
+	g.syncFrame()
+	at := g.spriteCur + 1
+	fr := g.sprite.Frames
+	g.sprite.Frames = append(fr[:at:at], append([]spriteFrame{f}, fr[at:]...)...)
+	g.spriteCur = at
+	if g.spriteMode() {
+		g.cells = f.Cells
+	}
+	g.scrollToFrame()
+	g.updatePreviewText()
+}
+
+// addFrame - nowa pusta klatka z czasem bieżącej
+func (g *Game) addFrame() {
+	g.insertFrame(spriteFrame{Duration: g.sprite.Frames[g.spriteCur].Duration})
+}
+
+// duplicateFrame - kopia bieżącej klatki (bez historii)
+func (g *Game) duplicateFrame() {
+	g.syncFrame()
+	f := g.sprite.Frames[g.spriteCur]
+	f.hist = nil
+	g.insertFrame(f)
+}
+
+// deleteFrame usuwa bieżącą klatkę; ostatnia klatka jest tylko czyszczona
+func (g *Game) deleteFrame() {
+	if len(g.sprite.Frames) == 1 {
+		g.sprite.Frames[0] = spriteFrame{Duration: g.sprite.Frames[0].Duration}
+		g.spriteCur = 0
+	} else {
+		at := g.spriteCur
+		g.sprite.Frames = append(g.sprite.Frames[:at:at], g.sprite.Frames[at+1:]...)
+		g.spriteCur = min(at, len(g.sprite.Frames)-1)
+	}
+	g.spritePlayFrame = clampInt(g.spritePlayFrame, 0, len(g.sprite.Frames)-1)
+	if g.spriteMode() {
+		g.cells = g.sprite.Frames[g.spriteCur].Cells
+	}
+	g.stroke.active = false
+	g.scrollToFrame()
+	g.updatePreviewText()
+}
+
+// setFrameDuration zmienia czas bieżącej klatki
+func (g *Game) setFrameDuration(ms int) {
+	g.sprite.Frames[g.spriteCur].Duration = clampInt(ms, MinFrameMs, MaxFrameMs)
+}
+
+// toggleSpritePlay - start / stop odtwarzania od pierwszej klatki
+func (g *Game) toggleSpritePlay() {
+	g.spritePlaying = !g.spritePlaying
+	g.spritePlayFrame = 0
+	g.spritePlayDir = 1
+	g.spriteElapsed = 0
+}
+
+// updateSprite synchronizuje siatkę z klatką i odtwarza animację
+func (g *Game) updateSprite() {
+	g.syncFrame()
+	if !g.spritePlaying {
+		return
+	}
+	g.spriteElapsed += 1000 / float64(ebiten.TPS())
+	for g.spritePlaying {
+		d := float64(g.sprite.Frames[g.spritePlayFrame].Duration)
+		if g.spriteElapsed < d {
+			break
+		}
+		g.spriteElapsed -= d
+		g.stepSprite()
+	}
+}
+
+// stepSprite przechodzi do następnej klatki wg trybu odtwarzania
+func (g *Game) stepSprite() {
+	n := len(g.sprite.Frames)
+	switch g.sprite.Mode {
+	case PlayOnce:
+		if g.spritePlayFrame+1 >= n {
+			g.spritePlaying = false
+			return
+		}
+		g.spritePlayFrame++
+	case PlayLoop:
+		g.spritePlayFrame = (g.spritePlayFrame + 1) % n
+	case PlayPingPong:
+		if n == 1 {
+			return
+		}
+		if next := g.spritePlayFrame + g.spritePlayDir; next < 0 || next >= n {
+			g.spritePlayDir = -g.spritePlayDir
+		}
+		g.spritePlayFrame += g.spritePlayDir
+	}
+}
+
+// spriteShown - klatka widoczna w podglądzie (odtwarzana albo edytowana)
+func (g *Game) spriteShown() Glyph {
+	if g.spritePlaying {
+		return g.sprite.Frames[g.spritePlayFrame].Cells
+	}
+	return g.sprite.Frames[g.spriteCur].Cells
+}
+
+// buildSpriteFrame - ramka dla matryc: widoczna klatka na każdej z 4 matryc
+func (g *Game) buildSpriteFrame() [][]int {
+	return tileFrame(g.spriteShown(), MatrixSize)
+}
+
+// tileFrame - klatka powtórzona na każdej matrycy paska (rows wierszy)
+func tileFrame(gl Glyph, rows int) [][]int {
+	frame := make([][]int, rows)
+	for y := range frame {
+		frame[y] = make([]int, MarqueeCols)
+		if y >= GridH {
+			continue
+		}
+		for x := range frame[y] {
+			if c := x % MatrixSize; c < GridW {
+				frame[y][x] = gl[y][c]
+			}
+		}
+	}
+	return frame
+}
+
+// updateSpriteKeys - klawisze listy w trybie animacji
+func (g *Game) updateSpriteKeys() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyPageUp) {
+		g.selectFrame(g.spriteCur - 1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyPageDown) {
+		g.selectFrame(g.spriteCur + 1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyInsert) {
+		g.addFrame()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyDelete) {
+		g.deleteFrame()
+	}
+}
+
+// exportSprite zapisuje animację do pliku C (bieżący tryb eksportu)
+func (g *Game) exportSprite(progmem bool) error {
+	g.syncFrame()
+	opts := g.exportOptions(progmem)
+	opts.Symbol = "anim"
+	opts.Widths = nil
+	opts.Metrics = nil
+	opts.Kerning = nil
+
+	var b strings.Builder
+	b.WriteString(GenerateCFromGlyphs(g.sprite.glyphs(), g.exportMode, opts))
+	writeDurations(&b, g.sprite, progmem)
+
+	if err := os.MkdirAll("export", os.ModePerm); err != nil {
+		return err
+	}
+	filename := "export/animacja.h"
+	if progmem {
+		filename = "export/animacja_progmem.h"
+	}
+	if err := os.WriteFile(filename, []byte(b.String()), 0o644); err != nil {
+		return err
+	}
+	g.lastExport = filename
+	return nil
+}
+
+// writeDurations dopisuje liczbę klatek i tabelę czasów (ms)
+func writeDurations(b *strings.Builder, d spriteDoc, progmem bool) {
+	pm := ""
+	if progmem {
+		pm = " PROGMEM"
+	}
+	b.WriteString(fmt.Sprintf("\n// czas wyświetlania klatek (ms), tryb w edytorze: %s\n", playModeNames[d.Mode]))
+	b.WriteString(fmt.Sprintf("#define ANIM_FRAMES %d\n", len(d.Frames)))
+	b.WriteString(fmt.Sprintf("const uint16_t anim_durations[ANIM_FRAMES]%s = {", pm))
+	for i, f := range d.Frames {
+		if i%8 == 0 {
+			b.WriteString("\n  ")
+		} else {
+			b.WriteString(" ")
+		}
+		b.WriteString(fmt.Sprintf("%d,", f.Duration))
+	}
+	b.WriteString("\n};\n")
+}
+
+// ----------------------
+// zakładka "Klatki"
+// ----------------------
+
+// frameStripRect - pasek miniatur klatek (2 rzędy)
+func frameStripRect() (x, y, w, h int) {
+	x0, y0, _, _ := sideRect()
+	return x0 + 8, y0 + 128, OptionsW - 16, 2 * framePitch
+}
+
+func framesPerRow() int {
+	_, _, w, _ := frameStripRect()
+	return w / framePitch
+}
+
+// scrollToFrame przewija pasek miniatur do bieżącej klatki
+func (g *Game) scrollToFrame() {
+	row := g.spriteCur / framesPerRow()
+	if row < g.spriteScroll {
+		g.spriteScroll = row
+	} else if row > g.spriteScroll+1 {
+		g.spriteScroll = row - 1
+	}
+}
+
+// scrollFrames przewija pasek miniatur kółkiem myszy
+func (g *Game) scrollFrames(wy float64) {
+	rows := (len(g.sprite.Frames) + framesPerRow() - 1) / framesPerRow()
+	g.spriteScroll = clampInt(g.spriteScroll-int(wy), 0, max(0, rows-2))
+}
+
+// spriteButtons - przyciski zakładki "Klatki"
+func (g *Game) spriteButtons() []uiButton {
+	x0, y0, _, _ := sideRect()
+	play := "Odtwórz"
+	if g.spritePlaying {
+		play = "Stop"
+	}
+	stream := "Matryce: nie"
+	if g.spriteStream {
+		stream = "Matryce: tak"
+	}
+	edit := "Edycja: nie"
+	if g.spriteEdit {
+		edit = "Edycja: tak"
+	}
+	return []uiButton{
+		{x0 + 160, y0 + 2, 132, 24, edit, func() { g.setSpriteEdit(!g.spriteEdit) }},
+		{x0 + 8, y0 + 30, 92, 24, "Nowa", g.addFrame},
+		{x0 + 104, y0 + 30, 92, 24, "Duplikuj", g.duplicateFrame},
+		{x0 + 200, y0 + 30, 92, 24, "Usuń", g.deleteFrame},
+		{x0 + 120, y0 + 62, 34, 22, "-", func() { g.setFrameDuration(g.sprite.Frames[g.spriteCur].Duration - FrameMsStep) }},
+		{x0 + 158, y0 + 62, 34, 22, "+", func() { g.setFrameDuration(g.sprite.Frames[g.spriteCur].Duration + FrameMsStep) }},
+		{x0 + 196, y0 + 62, 96, 22, "Wszystkim", g.applyDurationToAll},
+		{x0 + 8, y0 + 94, 80, 24, play, g.toggleSpritePlay},
+		{x0 + 92, y0 + 94, 96, 24, playModeNames[g.sprite.Mode], func() { cycle(&g.sprite.Mode, playModeCount, 1) }},
+		{x0 + 192, y0 + 94, 100, 24, stream, func() { g.spriteStream = !g.spriteStream }},
+		{x0 + 160, y0 + 246, 132, 24, "Eksport C", func() { g.exportSpriteStatus(false) }},
+		{x0 + 160, y0 + 276, 132, 24, "PROGMEM", func() { g.exportSpriteStatus(true) }},
+	}
+}
+
+// applyDurationToAll - czas bieżącej klatki dla wszystkich klatek
+func (g *Game) applyDurationToAll() {
+	d := g.sprite.Frames[g.spriteCur].Duration
+	for i := range g.sprite.Frames {
+		g.sprite.Frames[i].Duration = d
+	}
+}
+
+// exportSpriteStatus - eksport z komunikatem błędu w pasku stanu
+func (g *Game) exportSpriteStatus(progmem bool) {
+	if err := g.exportSprite(progmem); err != nil {
+		g.lastExport = "Błąd eksportu animacji"
+	}
+}
+
+// handleSpriteClick - LPM w zakładce "Klatki"
+func (g *Game) handleSpriteClick(x, y, dir int) {
+	if dir < 0 {
+		return
+	}
+	if clickButtons(g.spriteButtons(), x, y) {
+		return
+	}
+	sx, sy, sw, sh := frameStripRect()
+	if x >= sx && x < sx+sw && y >= sy && y < sy+sh {
+		col := (x - sx) / framePitch
+		row := (y-sy)/framePitch + g.spriteScroll
+		if i := row*framesPerRow() + col; col < framesPerRow() && i < len(g.sprite.Frames) {
+			g.selectFrame(i)
+		}
+	}
+}
+
+// drawFrameThumb rysuje klatkę w kwadracie size x size
+func drawFrameThumb(screen *ebiten.Image, gl Glyph, x, y, size int) {
+	px := max(1, size/max(GridW, GridH))
+	fillRect(screen, x, y, size, size, color.RGBA{R: 0x08, G: 0x08, B: 0x08, A: 0xff})
+	for cy := 0; cy < GridH; cy++ {
+		for cx := 0; cx < GridW; cx++ {
+			if v := gl[cy][cx]; v != 0 {
+				fillRect(screen, x+cx*px, y+cy*px, px, px, cellColor(v))
+			}
+		}
+	}
+}
+
+// drawSpriteTab rysuje zakładkę "Klatki"
+func (g *Game) drawSpriteTab(screen *ebiten.Image) {
+	x0, y0, _, _ := sideRect()
+	cur := g.sprite.Frames[g.spriteCur]
+	drawText(screen, fmt.Sprintf("Klatka %d / %d", g.spriteCur+1, len(g.sprite.Frames)), x0+8, y0+20)
+	drawText(screen, fmt.Sprintf("Czas: %d ms", cur.Duration), x0+8, y0+78)
+
+	for _, b := range g.spriteButtons() {
+		col := color.Color(btnColor)
+		if b.label == "Stop" || b.label == "Matryce: tak" || b.label == "Edycja: tak" {
+			col = btnColorAct
+		}
+		b.draw(screen, col)
+	}
+
+	// miniatury klatek
+	sx, sy, _, _ := frameStripRect()
+	per := framesPerRow()
+	for r := 0; r < 2; r++ {
+		for c := 0; c < per; c++ {
+			i := (g.spriteScroll+r)*per + c
+			if i >= len(g.sprite.Frames) {
+				break
+			}
+			tx, ty := sx+c*framePitch, sy+r*framePitch
+			if i == g.spriteCur {
+				fillRect(screen, tx-2, ty-2, frameThumb+4, frameThumb+4, btnColorAct)
+			} else if g.spritePlaying && i == g.spritePlayFrame {
+				fillRect(screen, tx-2, ty-2, frameThumb+4, frameThumb+4, color.RGBA{R: 0xFF, G: 0xC0, B: 0x40, A: 0xff})
+			}
+			drawFrameThumb(screen, g.sprite.Frames[i].Cells, tx, ty, frameThumb)
+		}
+	}
+
+	// podgląd odtwarzania
+	drawText(screen, "Podgląd", x0+8, y0+212)
+	drawFrameThumb(screen, g.spriteShown(), x0+8, y0+220, 80)
+	shown := g.spriteCur
+	if g.spritePlaying {
+		shown = g.spritePlayFrame
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("klatka %d", shown+1), x0+96, y0+222)
+	total := 0
+	for _, f := range g.sprite.Frames {
+		total += f.Duration
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("cykl %d ms", total), x0+96, y0+238)
+	drawText(screen, "Eksport animacji:", x0+160, y0+238)
+}
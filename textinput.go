@@ -0,0 +1,123 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: textinput.go
+
+Jednoliniowe pole tekstowe. Aktywne pole (Game.focus) przejmuje klawiaturę,
+skróty jednoklawiszowe (M, C, E ...) są wtedy wyłączone.
+Enter zatwierdza, Esc lub kliknięcie poza polem kończy edycję.
+Pole z validate pokazuje błąd pod spodem i nie przyjmuje Enter, dopóki tekst jest zły.
+
+*/
+
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// textInput - pole tekstowe
+type textInput struct {
+	text     string
+	maxLen   int
+	onEnter  func(s string) // wywoływane po Enter
+	onChange func(s string) // wywoływane po każdej zmianie tekstu
+	validate func(s string) error
+	err      string // błąd walidacji wyświetlany pod polem
+}
+
+// update obsługuje klawiaturę aktywnego pola; zwraca false gdy edycja się skończyła
+func (t *textInput) update() bool {
+	changed := false
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if t.maxLen > 0 && len([]rune(t.text)) >= t.maxLen {
+			break
+		}
+		t.text += string(r)
+		changed = true
+	}
+
+	if repeatingKey(ebiten.KeyBackspace) && t.text != "" {
+		rs := []rune(t.text)
+		t.text = string(rs[:len(rs)-1])
+		changed = true
+	}
+
+	if changed && t.onChange != nil {
+		t.onChange(t.text)
+	}
+	if changed {
+		t.check()
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		if !t.check() {
+			return true
+		}
+		if t.onEnter != nil {
+			t.onEnter(t.text)
+		}
+		return false
+	}
+	return !inpututil.IsKeyJustPressed(ebiten.KeyEscape)
+}
+
+// check sprawdza tekst funkcją validate; false = błąd (zapisany w t.err)
+func (t *textInput) check() bool {
+	t.err = ""
+	if t.validate == nil {
+		return true
+	}
+	if err := t.validate(t.text); err != nil {
+		t.err = err.Error()
+		return false
+	}
+	return true
+}
+
+// draw rysuje pole; active = pole ma fokus (kursor na końcu tekstu)
+func (t *textInput) draw(screen *ebiten.Image, x, y, w, h int, active bool) {
+	bg := color.RGBA{R: 0x22, G: 0x22, B: 0x26, A: 0xff}
+	if active {
+		bg = color.RGBA{R: 0x2A, G: 0x2A, B: 0x40, A: 0xff}
+	}
+	fillRect(screen, x, y, w, h, bg)
+	s := t.text
+	if active {
+		s += "_"
+	}
+	drawText(screen, s, x+6, y+h/2+6)
+
+	if active && t.err != "" {
+		fillRect(screen, x, y+h, len([]rune(t.err))*6+8, 16, color.RGBA{R: 0x90, G: 0x20, B: 0x20, A: 0xff})
+		ebitenutil.DebugPrintAt(screen, t.err, x+4, y+h)
+	}
+}
+
+// repeatingKey - klawisz wciśnięty teraz lub przytrzymany (autopowtarzanie)
+func repeatingKey(k ebiten.Key) bool {
+	d := inpututil.KeyPressDuration(k)
+	return d == 1 || (d >= 30 && d%4 == 0)
+}
+
+// focusInput ustawia aktywne pole tekstowe (nil = brak)
+func (g *Game) focusInput(t *textInput) {
+	g.focus = t
+}
+
+// updateFocus obsługuje klawiaturę aktywnego pola; zwraca true gdy pole
+// przejęło klawiaturę w tej klatce
+func (g *Game) updateFocus() bool {
+	if g.focus == nil {
+		return false
+	}
+	if !g.focus.update() {
+		g.focus = nil
+	}
+	return true
+}
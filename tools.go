@@ -0,0 +1,296 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: tools.go
+
+Narzędzia rysowania na siatce edytora:
+- Ołówek (B):   LPM maluje z przeciąganiem - pierwsza komórka działa jak
+                dawniej (przełącza), reszta pociągnięcia dostaje ten sam kolor
+- Linia (L), Prostokąt (R), Elipsa (O): przeciągnij od narożnika do narożnika
+- Wypełnij (F): wypełnia obszar tego samego koloru
+- "Pełne":      prostokąt i elipsa wypełnione zamiast obrysu
+- PPM w każdym narzędziu gasi piksele (gumka)
+
+Kolor: tryb 2-BIT - kolor A (z Shift kolor B), pozostałe tryby - kolor rysowania.
+
+*/
+
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// narzędzia rysowania
+const (
+	ToolPen = iota
+	ToolLine
+	ToolRect
+	ToolEllipse
+	ToolFill
+
+	toolCount
+)
+
+var toolNames = [toolCount]string{"Ołówek", "Linia", "Prost.", "Elipsa", "Wypełnij"}
+
+var toolKeys = [toolCount]ebiten.Key{ebiten.KeyB, ebiten.KeyL, ebiten.KeyR, ebiten.KeyO, ebiten.KeyF}
+
+// stroke - trwające pociągnięcie myszą po siatce
+type stroke struct {
+	active bool
+	button ebiten.MouseButton
+	value  int   // wartość malowanych komórek
+	x0, y0 int   // komórka początkowa
+	lx, ly int   // ostatnia komórka (ołówek)
+	base   Glyph // siatka przed pociągnięciem (podgląd kształtu)
+}
+
+// toolButtons - pasek narzędzi pod suwakiem koloru
+func (g *Game) toolButtons() []uiButton {
+	x0 := 12
+	y0 := g.colorSliderY + g.colorSliderH + 12
+	bw := (EditorSize - 24) / 3
+	buttons := make([]uiButton, 0, toolCount+1)
+	for i := 0; i < int(toolCount); i++ {
+		tool := i
+		buttons = append(buttons, uiButton{
+			x0 + (i%3)*bw, y0 + (i/3)*28, bw - 4, 24,
+			toolNames[i], func() { g.tool = tool },
+		})
+	}
+	buttons = append(buttons, uiButton{
+		x0 + 2*bw, y0 + 28, bw - 4, 24,
+		"Pełne", func() { g.shapeFilled = !g.shapeFilled },
+	})
+	return buttons
+}
+
+// drawToolbar rysuje pasek narzędzi
+func (g *Game) drawToolbar(screen *ebiten.Image) {
+	for i, b := range g.toolButtons() {
+		col := color.Color(btnColor)
+		if i == g.tool || (i == int(toolCount) && g.shapeFilled) {
+			col = btnColorAct
+		}
+		b.draw(screen, col)
+	}
+}
+
+// toggleValue - dawna obsługa kliknięcia: nowa wartość komórki v wg trybu
+func (g *Game) toggleValue(v int) int {
+	switch g.mode {
+	case ModeMono:
+		if v != 0 {
+			return 0
+		}
+		return g.monoColor
+	case ModeTwo:
+		if v == 0 {
+			return g.twoA
+		} else if v == g.twoA {
+			return g.twoB
+		}
+		return 0
+	}
+	// RGB i WS2812B: ten sam kolor gasi diodę
+	if v == g.monoColor {
+		return 0
+	}
+	return g.monoColor
+}
+
+// paintValue - kolor malowania kształtów wg trybu
+func (g *Game) paintValue() int {
+	if g.mode == ModeTwo {
+		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+			return g.twoB
+		}
+		return g.twoA
+	}
+	return g.monoColor
+}
+
+// cellAt zwraca komórkę siatki pod kursorem
+func cellAt(x, y int) (cx, cy int, ok bool) {
+	if x < 0 || y < 0 {
+		return 0, 0, false
+	}
+	cx, cy = x/CellSize, y/CellSize
+	return cx, cy, cx < GridW && cy < GridH
+}
+
+// beginStroke rozpoczyna rysowanie w komórce (cx,cy)
+func (g *Game) beginStroke(cx, cy int, button ebiten.MouseButton) {
+	g.pushUndo()
+
+	erase := button == ebiten.MouseButtonRight
+	s := stroke{active: true, button: button, x0: cx, y0: cy, lx: cx, ly: cy, base: Glyph(g.cells)}
+
+	switch {
+	case erase:
+		s.value = 0
+	case g.tool == ToolPen:
+		s.value = g.toggleValue(g.cells[cy][cx])
+	default:
+		s.value = g.paintValue()
+	}
+
+	if g.tool == ToolFill {
+		g.floodFill(cx, cy, s.value)
+		g.updatePreviewText()
+		return
+	}
+
+	g.stroke = s
+	g.continueStroke(cx, cy)
+}
+
+// continueStroke - kursor przesunął się na komórkę (cx,cy)
+func (g *Game) continueStroke(cx, cy int) {
+	s := &g.stroke
+	set := func(x, y int) {
+		if x >= 0 && x < GridW && y >= 0 && y < GridH {
+			g.cells[y][x] = s.value
+		}
+	}
+
+	switch g.tool {
+	case ToolPen:
+		plotLine(s.lx, s.ly, cx, cy, set)
+		s.lx, s.ly = cx, cy
+	case ToolLine:
+		g.cells = s.base
+		plotLine(s.x0, s.y0, cx, cy, set)
+	case ToolRect:
+		g.cells = s.base
+		plotRect(s.x0, s.y0, cx, cy, g.shapeFilled, set)
+	case ToolEllipse:
+		g.cells = s.base
+		plotEllipse(s.x0, s.y0, cx, cy, g.shapeFilled, set)
+	}
+
+	// podgląd HEX/BIN na bieżąco (eksport czcionki zmienia dopiero zapis znaku)
+	if g.previewGrid {
+		g.updateGridPreview()
+	}
+}
+
+// updateStroke - wywoływane w każdej klatce podczas rysowania
+func (g *Game) updateStroke(x, y int) {
+	if !g.stroke.active {
+		return
+	}
+	if !ebiten.IsMouseButtonPressed(g.stroke.button) {
+		g.stroke.active = false
+		g.updatePreviewText()
+		return
+	}
+	// poza siatką kształt "przykleja" się do krawędzi
+	cx := clampInt(x/CellSize, 0, GridW-1)
+	cy := clampInt(y/CellSize, 0, GridH-1)
+	g.continueStroke(cx, cy)
+}
+
+// floodFill wypełnia wartością v obszar (4-sąsiedztwo) o kolorze komórki (cx,cy)
+func (g *Game) floodFill(cx, cy, v int) {
+	from := g.cells[cy][cx]
+	if from == v {
+		return
+	}
+	stack := [][2]int{{cx, cy}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		x, y := p[0], p[1]
+		if x < 0 || x >= GridW || y < 0 || y >= GridH || g.cells[y][x] != from {
+			continue
+		}
+		g.cells[y][x] = v
+		stack = append(stack, [2]int{x + 1, y}, [2]int{x - 1, y}, [2]int{x, y + 1}, [2]int{x, y - 1})
+	}
+}
+
+// plotLine - odcinek (Bresenham)
+func plotLine(x0, y0, x1, y1 int, set func(x, y int)) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		set(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// plotRect - prostokąt między narożnikami (obrys lub wypełniony)
+func plotRect(x0, y0, x1, y1 int, filled bool, set func(x, y int)) {
+	x0, x1 = min(x0, x1), max(x0, x1)
+	y0, y1 = min(y0, y1), max(y0, y1)
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			if filled || x == x0 || x == x1 || y == y0 || y == y1 {
+				set(x, y)
+			}
+		}
+	}
+}
+
+// plotEllipse - elipsa wpisana w prostokąt między narożnikami.
+// Na tak małej siatce liczymy środki komórek: wypełnienie to komórki
+// wewnątrz elipsy, obrys to te z nich, które mają sąsiada na zewnątrz.
+func plotEllipse(x0, y0, x1, y1 int, filled bool, set func(x, y int)) {
+	x0, x1 = min(x0, x1), max(x0, x1)
+	y0, y1 = min(y0, y1), max(y0, y1)
+	cx := float64(x0+x1+1) / 2
+	cy := float64(y0+y1+1) / 2
+	rx := float64(x1-x0+1) / 2
+	ry := float64(y1-y0+1) / 2
+
+	inside := func(x, y int) bool {
+		if x < x0 || x > x1 || y < y0 || y > y1 {
+			return false
+		}
+		dx := (float64(x) + 0.5 - cx) / rx
+		dy := (float64(y) + 0.5 - cy) / ry
+		return dx*dx+dy*dy <= 1
+	}
+
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			if !inside(x, y) {
+				continue
+			}
+			if filled || !inside(x-1, y) || !inside(x+1, y) || !inside(x, y-1) || !inside(x, y+1) {
+				set(x, y)
+			}
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// plotCells zbiera komórki postawione przez funkcję rysującą
+func plotCells(plot func(set func(x, y int))) map[[2]int]bool {
+	cells := map[[2]int]bool{}
+	plot(func(x, y int) { cells[[2]int{x, y}] = true })
+	return cells
+}
+
+// TestPlotEllipse - elipsa symetryczna, obrys zawiera się w wypełnieniu,
+// kolejność narożników bez znaczenia
+func TestPlotEllipse(t *testing.T) {
+	boxes := [][4]int{{0, 0, 4, 4}, {0, 0, 7, 7}, {1, 2, 6, 4}, {2, 2, 2, 2}, {0, 0, 0, 5}}
+	for _, r := range boxes {
+		t.Run(fmt.Sprint(r), func(t *testing.T) {
+			x0, y0, x1, y1 := r[0], r[1], r[2], r[3]
+			filled := plotCells(func(set func(x, y int)) { plotEllipse(x0, y0, x1, y1, true, set) })
+			outline := plotCells(func(set func(x, y int)) { plotEllipse(x0, y0, x1, y1, false, set) })
+			swapped := plotCells(func(set func(x, y int)) { plotEllipse(x1, y1, x0, y0, true, set) })
+
+			if len(filled) == 0 {
+				t.Fatal("pusta elipsa")
+			}
+			if len(swapped) != len(filled) {
+				t.Errorf("zamienione narożniki: %d komórek, want %d", len(swapped), len(filled))
+			}
+			for p := range filled {
+				if p[0] < x0 || p[0] > x1 || p[1] < y0 || p[1] > y1 {
+					t.Errorf("komórka %v poza prostokątem", p)
+				}
+				if !filled[[2]int{x0 + x1 - p[0], p[1]}] || !filled[[2]int{p[0], y0 + y1 - p[1]}] {
+					t.Errorf("elipsa niesymetryczna w %v", p)
+				}
+			}
+			for p := range outline {
+				if !filled[p] {
+					t.Errorf("obrys %v poza wypełnieniem", p)
+				}
+			}
+		})
+	}
+
+	// 5x5: narożniki poza elipsą, środek tylko w wypełnieniu
+	filled := plotCells(func(set func(x, y int)) { plotEllipse(0, 0, 4, 4, true, set) })
+	outline := plotCells(func(set func(x, y int)) { plotEllipse(0, 0, 4, 4, false, set) })
+	if filled[[2]int{0, 0}] || filled[[2]int{4, 4}] {
+		t.Error("narożnik w elipsie 5x5")
+	}
+	if !filled[[2]int{2, 2}] || outline[[2]int{2, 2}] {
+		t.Error("środek elipsy 5x5: want w wypełnieniu, nie w obrysie")
+	}
+	if !outline[[2]int{2, 0}] || !outline[[2]int{0, 2}] {
+		t.Error("obrys 5x5 bez skrajnych komórek osi")
+	}
+}
+
+// TestFloodFill - wypełnienie zatrzymuje się na innym kolorze i brzegu siatki
+func TestFloodFill(t *testing.T) {
+	setGridSize(t, 8, 8)
+	g := &Game{}
+	for y := 0; y < GridH; y++ {
+		g.cells[y][3] = 1 // ściana
+	}
+
+	// komórki ukryte poza siatką (glyphsize.go) zostają nietknięte
+	g.floodFill(0, 0, 2)
+	for y := 0; y < MaxGridH; y++ {
+		for x := 0; x < MaxGridW; x++ {
+			want := 0
+			switch {
+			case x == 3 && y < GridH:
+				want = 1
+			case x < 3 && y < GridH:
+				want = 2
+			}
+			if g.cells[y][x] != want {
+				t.Fatalf("cells[%d][%d] = %d, want %d", y, x, g.cells[y][x], want)
+			}
+		}
+	}
+
+	// ten sam kolor - bez zmian
+	before := g.cells
+	g.floodFill(1, 1, 2)
+	if g.cells != before {
+		t.Error("floodFill tym samym kolorem zmienił siatkę")
+	}
+
+	// wypełnienie ściany
+	g.floodFill(3, 5, 4)
+	for y := 0; y < GridH; y++ {
+		if g.cells[y][3] != 4 || g.cells[y][2] != 2 || g.cells[y][4] != 0 {
+			t.Fatalf("wiersz %d po wypełnieniu ściany: %v", y, g.cells[y][:GridW])
+		}
+	}
+}
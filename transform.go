@@ -0,0 +1,242 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: transform.go
+
+Przekształcenia bieżącego znaku (zakładka "Znak" panelu bocznego):
+- przesunięcie (strzałki), z zawijaniem lub bez (W przełącza zawijanie)
+- obrót 90° (T), 180° (U), 270° (Shift+T)
+- lustro poziome (H) i pionowe (V)
+- negatyw (N): w MONO / 2-KOLORY zapalone <-> zgaszone,
+  w RGB / WS2812B odwrócenie składowych koloru
+- pod spodem przyciski listy znaków (glyph.go): wstaw, nowy, duplikuj, usuń
+  oraz szerokość znaku czcionki proporcjonalnej (proportional.go)
+
+Każda operacja to jeden krok cofania (Ctrl+Z).
+
+*/
+
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// glyphOp - przekształcenie znaku z przyciskiem i skrótem klawiszowym
+type glyphOp struct {
+	label string
+	key   ebiten.Key
+	shift bool // skrót z wciśniętym Shift
+	apply func(gl Glyph) Glyph
+}
+
+// glyphOps zwraca listę przekształceń (kolejność = kolejność przycisków)
+func (g *Game) glyphOps() []glyphOp {
+	shift := func(dx, dy int) func(Glyph) Glyph {
+		return func(gl Glyph) Glyph { return shiftGlyph(gl, dx, dy, g.shiftWrap) }
+	}
+	return []glyphOp{
+		{"Lewo", ebiten.KeyArrowLeft, false, shift(-1, 0)},
+		{"Prawo", ebiten.KeyArrowRight, false, shift(1, 0)},
+		{"Góra", ebiten.KeyArrowUp, false, shift(0, -1)},
+		{"Dół", ebiten.KeyArrowDown, false, shift(0, 1)},
+		{"90°", ebiten.KeyT, false, func(gl Glyph) Glyph { return rotateGlyph(gl, 1) }},
+		{"180°", ebiten.KeyU, false, func(gl Glyph) Glyph { return rotateGlyph(gl, 2) }},
+		{"270°", ebiten.KeyT, true, func(gl Glyph) Glyph { return rotateGlyph(gl, 3) }},
+		{"Lustro H", ebiten.KeyH, false, flipGlyphH},
+		{"Lustro V", ebiten.KeyV, false, flipGlyphV},
+		{"Negatyw", ebiten.KeyN, false, g.invertGlyph},
+	}
+}
+
+// transformCells stosuje przekształcenie do siatki jako jeden krok cofania
+func (g *Game) transformCells(fn func(Glyph) Glyph) {
+	g.pushUndo()
+	g.cells = fn(Glyph(g.cells))
+	g.updatePreviewText()
+}
+
+// updateTransformKeys - skróty klawiszowe przekształceń (bez Ctrl)
+func (g *Game) updateTransformKeys() {
+	if ebiten.IsKeyPressed(ebiten.KeyControl) {
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyW) {
+		g.shiftWrap = !g.shiftWrap
+	}
+	shiftDown := ebiten.IsKeyPressed(ebiten.KeyShift)
+	for _, op := range g.glyphOps() {
+		if op.shift != shiftDown {
+			continue
+		}
+		pressed := inpututil.IsKeyJustPressed(op.key)
+		switch op.key {
+		case ebiten.KeyArrowLeft, ebiten.KeyArrowRight, ebiten.KeyArrowUp, ebiten.KeyArrowDown:
+			// strzałki powtarzają się przy przytrzymaniu
+			pressed = repeatingKey(op.key)
+		}
+		if pressed {
+			g.transformCells(op.apply)
+		}
+	}
+}
+
+// shiftGlyph przesuwa znak o (dx,dy); bez zawijania wsuwa zgaszone piksele
+func shiftGlyph(gl Glyph, dx, dy int, wrap bool) Glyph {
+	var out Glyph
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			sx, sy := x-dx, y-dy
+			if wrap {
+				sx = (sx%GridW + GridW) % GridW
+				sy = (sy%GridH + GridH) % GridH
+			} else if sx < 0 || sx >= GridW || sy < 0 || sy >= GridH {
+				continue
+			}
+			out[y][x] = gl[sy][sx]
+		}
+	}
+	return out
+}
+
+// rotateGlyph obraca znak o quarter*90° w prawo; na siatce prostokątnej
+// piksele, które wychodzą poza GridW x GridH, są obcinane
+func rotateGlyph(gl Glyph, quarter int) Glyph {
+	for q := 0; q < (quarter%4+4)%4; q++ {
+		var out Glyph
+		for y := 0; y < GridH; y++ {
+			for x := 0; x < GridW; x++ {
+				if nx, ny := GridH-1-y, x; nx < GridW && ny < GridH {
+					out[ny][nx] = gl[y][x]
+				}
+			}
+		}
+		gl = out
+	}
+	return gl
+}
+
+// flipGlyphH - lustro poziome (lewo <-> prawo)
+func flipGlyphH(gl Glyph) Glyph {
+	var out Glyph
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			out[y][GridW-1-x] = gl[y][x]
+		}
+	}
+	return out
+}
+
+// flipGlyphV - lustro pionowe (góra <-> dół)
+func flipGlyphV(gl Glyph) Glyph {
+	var out Glyph
+	for y := 0; y < GridH; y++ {
+		out[GridH-1-y] = gl[y]
+	}
+	return out
+}
+
+// invertGlyph - negatyw znaku wg trybu edycji
+func (g *Game) invertGlyph(gl Glyph) Glyph {
+	var out Glyph
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			out[y][x] = g.invertValue(gl[y][x])
+		}
+	}
+	return out
+}
+
+// invertValue - negatyw pojedynczej komórki
+func (g *Game) invertValue(v int) int {
+	switch g.mode {
+	case ModeMono, ModeTwo:
+		if v != 0 {
+			return 0
+		}
+		return g.paintValue()
+	}
+
+	// RGB / WS2812B: odwrócone składowe; kolor z palety zostaje
+	// indeksem, jeśli negatyw jest w palecie
+	c := cellColor(v)
+	return colorCell(color.RGBA{R: 255 - c.R, G: 255 - c.G, B: 255 - c.B, A: 0xff})
+}
+
+// transformButtons - przyciski zakładki "Znak"
+func (g *Game) transformButtons() []uiButton {
+	x0, y0, _, _ := sideRect()
+	const perRow = 4
+	bw := (OptionsW - 16) / perRow
+	ops := g.glyphOps()
+	buttons := make([]uiButton, 0, len(ops)+1)
+
+	// rzędy: przesunięcia, obroty, lustra + negatyw
+	rows := [][]int{{0, 1, 2, 3}, {4, 5, 6}, {7, 8, 9}}
+	yy := y0 + 30
+	for _, row := range rows {
+		for col, i := range row {
+			op := ops[i]
+			buttons = append(buttons, uiButton{
+				x0 + 8 + col*bw, yy, bw - 4, 26,
+				op.label, func() { g.transformCells(op.apply) },
+			})
+		}
+		yy += 32
+	}
+	buttons = append(buttons, uiButton{
+		x0 + 8, yy, OptionsW - 16, 26,
+		"Zawijanie przy przesuwaniu (W)", func() { g.shiftWrap = !g.shiftWrap },
+	})
+	return buttons
+}
+
+// drawTransformTab rysuje zakładkę "Znak"
+func (g *Game) drawTransformTab(screen *ebiten.Image) {
+	x0, y0, _, _ := sideRect()
+	drawText(screen, "Przekształcenia znaku", x0+8, y0+20)
+
+	buttons := g.transformButtons()
+	for i, b := range buttons {
+		col := color.Color(btnColor)
+		if i == len(buttons)-1 && g.shiftWrap {
+			col = btnColorAct
+		}
+		b.draw(screen, col)
+	}
+
+	last := buttons[len(buttons)-1]
+	yy := last.y + last.h + 20
+	drawText(screen, "Lista znaków", x0+8, yy)
+	for _, b := range g.glyphListButtons(x0, yy+8) {
+		b.draw(screen, btnColor)
+	}
+	drawText(screen, "Ins / Shift+Ins, Del, Ctrl+D, PgUp / PgDn", x0+8, yy+84)
+
+	drawText(screen, g.advanceLabel(), x0+8, yy+118)
+	for _, b := range g.advanceButtons(x0, yy+100) {
+		b.draw(screen, btnColor)
+	}
+}
+
+// handleTransformClick - kliknięcie w zakładce "Znak"
+func (g *Game) handleTransformClick(x, y, dir int) {
+	if dir < 0 {
+		return
+	}
+	buttons := g.transformButtons()
+	if clickButtons(buttons, x, y) {
+		return
+	}
+	x0, _, _, _ := sideRect()
+	last := buttons[len(buttons)-1]
+	yy := last.y + last.h + 20
+	if clickButtons(g.glyphListButtons(x0, yy+8), x, y) {
+		return
+	}
+	clickButtons(g.advanceButtons(x0, yy+100), x, y)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestTransformRoundTrip - przekształcenie i jego odwrotność dają ten sam znak
+func TestTransformRoundTrip(t *testing.T) {
+	sizes := [][2]int{{8, 8}, {5, 7}, {16, 16}, {8, 16}}
+	for _, sz := range sizes {
+		t.Run(fmt.Sprintf("%dx%d", sz[0], sz[1]), func(t *testing.T) {
+			setGridSize(t, sz[0], sz[1])
+			gl := testGlyph(7, 3)
+
+			for _, d := range [][2]int{{1, 0}, {0, 1}, {-3, 2}, {GridW + 1, -GridH - 2}} {
+				if got := shiftGlyph(shiftGlyph(gl, d[0], d[1], true), -d[0], -d[1], true); got != gl {
+					t.Errorf("przesunięcie z zawijaniem o %v i z powrotem zmienia znak", d)
+				}
+			}
+			if got := flipGlyphH(flipGlyphH(gl)); got != gl {
+				t.Error("podwójne lustro poziome zmienia znak")
+			}
+			if got := flipGlyphV(flipGlyphV(gl)); got != gl {
+				t.Error("podwójne lustro pionowe zmienia znak")
+			}
+			if got := rotateGlyph(gl, 0); got != gl {
+				t.Error("obrót o 0 zmienia znak")
+			}
+			if GridW == GridH {
+				if got := rotateGlyph(gl, 4); got != gl {
+					t.Error("obrót o 4 x 90° zmienia znak")
+				}
+				if got := rotateGlyph(rotateGlyph(gl, 1), -1); got != gl {
+					t.Error("obrót w prawo i w lewo zmienia znak")
+				}
+				if got, want := rotateGlyph(gl, 2), flipGlyphH(flipGlyphV(gl)); got != want {
+					t.Error("obrót o 180° różni się od dwóch luster")
+				}
+			}
+		})
+	}
+}
+
+// TestShiftGlyphNoWrap - bez zawijania piksele wypadają, wchodzą zgaszone
+func TestShiftGlyphNoWrap(t *testing.T) {
+	setGridSize(t, 5, 7)
+	gl := testGlyph(7, 1)
+
+	got := shiftGlyph(shiftGlyph(gl, 1, 0, false), -1, 0, false)
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			want := gl[y][x]
+			if x == GridW-1 {
+				want = 0
+			}
+			if got[y][x] != want {
+				t.Fatalf("[%d][%d] = %d, want %d", y, x, got[y][x], want)
+			}
+		}
+	}
+}
+
+// TestRotateGlyph - obrót w prawo: lewy górny róg trafia w prawy górny
+func TestRotateGlyph(t *testing.T) {
+	setGridSize(t, 8, 8)
+	var gl Glyph
+	gl[0][0], gl[0][1] = 1, 2
+
+	got := rotateGlyph(gl, 1)
+	if got[0][7] != 1 || got[1][7] != 2 {
+		t.Errorf("obrót o 90°: [0][7]=%d [1][7]=%d, want 1 2", got[0][7], got[1][7])
+	}
+	if got := rotateGlyph(gl, -1); got[7][0] != 1 || got[6][0] != 2 {
+		t.Errorf("obrót o -90°: [7][0]=%d [6][0]=%d, want 1 2", got[7][0], got[6][0])
+	}
+}
@@ -11,11 +11,11 @@ package main
 import (
 	"fmt"
 	"image/color"
-	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 // Draw rysuje UI i podglądy
@@ -28,13 +28,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	// ----------------------
 	// Helper do rysowania prostokąta
 	// ----------------------
-	drawRect := func(img *ebiten.Image, x, y, w, h int, col color.Color) {
-		rect := ebiten.NewImage(w, h)
-		rect.Fill(col)
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(float64(x), float64(y))
-		img.DrawImage(rect, op)
-	}
+	drawRect := fillRect
 
 	// ----------------------
 	// 2. Siatka edytora
@@ -50,8 +44,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			// wypełnienie paletą
 			idx := g.cells[y][x]
 			if idx != 0 {
-				c := palette[idx]
-				drawRect(screen, px+3, py+3, CellSize-6, CellSize-6, c)
+				drawRect(screen, px+3, py+3, CellSize-6, CellSize-6, cellColor(idx))
 			}
 
 			// obramowanie
@@ -62,16 +55,36 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		}
 	}
 
+	// onion skin: duchy sąsiednich znaków
+	g.drawOnionSkin(screen)
+
+	// linie metryki: ascent, linia bazowa, descent
+	g.drawMetricGuides(screen)
+
+	// czcionka proporcjonalna: granica szerokości znaku
+	if g.proportional && !g.spriteMode() {
+		drawRect(screen, g.activeAdvance()*CellSize-1, 0, 2, GridH*CellSize, color.RGBA{R: 0xFF, G: 0xC0, B: 0x20, A: 0xff})
+	}
+
 	// 2A --- Przycisk: Dodaj znak ---
 	btnX := 12
-	btnY := GridH*CellSize + 12
-	btnW := GridW*CellSize - 24
+	btnY := EditorSize + 12
+	btnW := EditorSize - 24
 	btnH := 32
 
 	drawRect(screen, btnX, btnY, btnW, btnH, color.RGBA{R: 0x2A, G: 0x80, B: 0xFF, A: 0xff})
+	saveLabel := "Zapisz znak (%d)"
+	saveIdx := g.glyphIndex
+	if g.isNewGlyph() {
+		saveLabel = "Dodaj znak (%d)"
+	}
+	if g.spriteMode() {
+		saveLabel = "Edycja klatki %d" // tryb animacji (sprite.go)
+		saveIdx = g.spriteCur + 1
+	}
 	drawText(
 		screen,
-		fmt.Sprintf("Zapisz znak (%d)", g.glyphIndex),
+		fmt.Sprintf(saveLabel, saveIdx),
 		btnX+12,
 		btnY+22,
 	)
@@ -102,6 +115,9 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	handleColor := palette[idx] // kolor uchwytu = aktualny indeks
 	drawRect(screen, handleX-4, g.colorSliderY-2, 8, g.colorSliderH+4, handleColor)
 
+	// 2D. pasek narzędzi
+	g.drawToolbar(screen)
+
 	// ----------------------
 	// 3. Suwak prędkości animacji
 	// ----------------------
@@ -114,9 +130,9 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	// ----------------------
 	// 4. Panel po prawej - tło i przyciski
 	// ----------------------
-	x0 := GridW * CellSize
+	x0 := EditorSize
 	y0 := 0
-	drawRect(screen, x0, y0, CanvasW-x0, GridH*CellSize, color.RGBA{R: 0x18, G: 0x18, B: 0x1C, A: 0xff})
+	drawRect(screen, x0, y0, CanvasW-OptionsW-x0, EditorSize, color.RGBA{R: 0x18, G: 0x18, B: 0x1C, A: 0xff})
 
 	btnW = 180
 	btnH = 34
@@ -185,7 +201,11 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	// Podgląd HEX/BIN
 	by += btnH + pad
 	drawRect(screen, bx, by, btnW, btnH, color.RGBA{R: 0x30, G: 0x30, B: 0x36, A: 0xff})
-	drawText(screen, "Podgląd HEX/BIN", bx+8, by+23)
+	if g.previewGrid {
+		drawText(screen, "Podgląd: HEX/BIN", bx+8, by+23)
+	} else {
+		drawText(screen, "Podgląd: eksport", bx+8, by+23)
+	}
 
 	// Informacja o ostatnim eksporcie
 	drawText(screen, fmt.Sprintf("Plik: %s", g.lastExport), bx, by+btnH+pad+10)
@@ -193,122 +213,22 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	// ----------------------
 	// 5. Panel tekstowy z prawej - previewText
 	// ----------------------
-	textX := x0 + btnW + pad*2
-	textY := pad
-	w := CanvasW - textX - pad
-	h := CanvasH - textY - pad - 120
-	drawRect(screen, textX, textY, w, h, color.RGBA{R: 0x0E, G: 0x0E, B: 0x10, A: 0xff})
-
-	lines := strings.Split(g.previewText, "\n")
-	yline := textY + 6
-	for i, ln := range lines {
-		if i > 30 {
-			break
-		}
-		if len(ln) > 80 {
-			ln = ln[:80] + "..."
-		}
-		ebitenutil.DebugPrintAt(screen, ln, textX+6, yline)
-		yline += 12
-	}
+	g.drawCodeView(screen)
 
 	// ----------------------
-	// 6. Podgląd animacji
+	// 6. Podgląd animacji - przewijany tekst (marquee.go)
 	// ----------------------
-	playX := 12
-	playY := GridH*CellSize + 280
-	scaleW := 0.78
-	scaleH := 2.0
-	playW := int(float64(GridW*CellSize*4) * scaleW)
-	playH := int(float64(CellSize) * scaleH)
-	drawRect(screen, playX, playY, playW, playH, color.RGBA{R: 0x08, G: 0x08, B: 0x08, A: 0xff})
-
-	off := int(g.animX)
-	animSize := int(float64(CellSize) * AnimCellScale)
-	for y := 0; y < GridH; y++ {
-		for x := 0; x < GridW*4; x++ {
-			px := playX + x*animSize + off
-			py := playY + y*animSize
-			if px+animSize < playX || px > playX+playW {
-				continue
-			}
-			srcX := x % GridW
-			srcY := y
-			idx := g.cells[srcY][srcX]
-			if idx != 0 {
-				c := palette[idx]
-				drawRect(screen, px+1, py+1, animSize-2, animSize-2, c)
-			}
-		}
-	}
+	g.drawMarquee(screen)
 
 	// ----------------------
-	// 6A. Podgląd zapisanych znaków (glyphs)
+	// 6A. Przeglądarka zapisanych znaków (glyphs)
 	// ----------------------
-	glyphX := textX
-	glyphY := textY + h + 12
-
-	scale := 3   // powiększenie pojedynczego piksela
-	spacing := 6 // odstęp
-	//perRow := 8  // ile znaków w jednym rzędzie
-
-	// ile glyphów pokazujemy naraz
-	const glyphsPerView = 8
+	g.drawGlyphBrowser(screen)
 
-	start := g.glyphViewOfs
-	end := start + glyphsPerView
-	if end > len(g.glyphs) {
-		end = len(g.glyphs)
-	}
-
-	for i := start; i < end; i++ {
-		glyph := g.glyphs[i]
-
-		// pozycja tylko w jednym wierszu
-		cx := i - start
-		x := glyphX + cx*(8*scale+spacing)
-		y := glyphY
-
-		// tło miniatury
-		drawRect(
-			screen,
-			x-2,
-			y-2,
-			8*scale+4,
-			8*scale+4,
-			color.RGBA{R: 0x22, G: 0x22, B: 0x26, A: 0xff},
-		)
-
-		// podświetlenie aktywnego znaku
-		if i == g.activeGlyph {
-			drawRect(
-				screen,
-				x-4,
-				y-4,
-				8*scale+8,
-				8*scale+8,
-				color.RGBA{R: 0x2A, G: 0x80, B: 0xFF, A: 0xff},
-			)
-		}
-
-		// sam glyph
-		drawGlyphPreview(
-			screen,
-			glyph,
-			x,
-			y,
-			scale,
-			color.White,
-		)
-
-		// numer znaku
-		ebitenutil.DebugPrintAt(
-			screen,
-			fmt.Sprintf("%d", i),
-			x,
-			y+8*scale+2,
-		)
-	}
+	// ----------------------
+	// 6A+. Panel boczny (zakładki + podgląd WS2812)
+	// ----------------------
+	g.drawSidePanel(screen)
 
 	// ----------------------
 	// 6B. Status Serial
@@ -322,47 +242,92 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	// 7. Pomoc u dołu
 	// ----------------------
 	helpY := CanvasH - 20
-	help := "LPM: kliknij komórkę aby zmienić; M: zmiana trybu; C: wyczyść. Kliknij przyciski po prawej."
+	help := "LPM: rysuj, PPM: gumka; B/L/R/O/F: narzędzia; M: tryb; C: wyczyść; Ctrl+Z/Y: cofnij/ponów."
 	text.Draw(screen, help, fontFace, 8, helpY, color.White)
 }
 
 func drawGlyphPreview(
 	screen *ebiten.Image,
-	glyph []byte,
+	glyph Glyph,
 	x, y int,
 	scale int,
-	col color.Color,
 ) {
-	for row := 0; row < 8; row++ {
-		b := glyph[row]
-		for bit := 0; bit < 8; bit++ {
-			if (b & (1 << (7 - bit))) != 0 {
-				rect := ebiten.NewImage(scale, scale)
-				rect.Fill(col)
-
-				op := &ebiten.DrawImageOptions{}
-				op.GeoM.Translate(
-					float64(x+bit*scale),
-					float64(y+row*scale),
-				)
-				screen.DrawImage(rect, op)
+	for row := 0; row < GridH; row++ {
+		for bit := 0; bit < GridW; bit++ {
+			if idx := glyph[row][bit]; idx != 0 {
+				fillRect(screen, x+bit*scale, y+row*scale, scale, scale, cellColor(idx))
 			}
 		}
 	}
 }
 
-func drawText(screen *ebiten.Image, s string, x, y int) {
-	text.Draw(screen, s, fontFace, x, y, color.White)
+// drawWS2812Preview rysuje wyświetlacz WS2812 w układzie g.ws2812Layout obok siebie:
+// "ekran" - kolory palety z linią łańcucha od pierwszej diody (zielona kropka),
+// "diody" - symulacja po kalibracji (gamma, balans bieli, maks. jasność)
+func (g *Game) drawWS2812Preview(screen *ebiten.Image, x0, y0, w, h int) {
+	fillRect(screen, x0, y0, w, h, color.RGBA{R: 0x0E, G: 0x0E, B: 0x10, A: 0xff})
+	drawText(screen, "Podgląd WS2812", x0+8, y0+18)
+
+	l := g.ws2812Layout
+	dw, dh := l.Size()
+	half := (w - 24) / 2
+	cell := half / dw
+	if c := (h - 72) / dh; c < cell {
+		cell = c
+	}
+	oy := y0 + 42
+	oxScreen := x0 + 8
+	oxLED := x0 + 16 + half
+	ebitenutil.DebugPrintAt(screen, "ekran", oxScreen, y0+24)
+	ebitenutil.DebugPrintAt(screen, "diody", oxLED, y0+24)
+
+	// pozycje diod w kolejności łańcucha
+	centers := make([][2]float32, l.NumLEDs())
+	frame := g.buildWS2812Display()
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			px := oxScreen + x*cell
+			py := oy + y*cell
+			lx := oxLED + x*cell
+			fillRect(screen, px, py, cell-1, cell-1, color.RGBA{R: 0x22, G: 0x22, B: 0x26, A: 0xff})
+			fillRect(screen, lx, py, cell-1, cell-1, color.RGBA{R: 0x22, G: 0x22, B: 0x26, A: 0xff})
+			if v := frame[y][x]; v != 0 {
+				fillRect(screen, px+1, py+1, cell-3, cell-3, cellColor(v))
+				fillRect(screen, lx+1, py+1, cell-3, cell-3, simulateLED(g.ledCalib.Apply(cellColor(v))))
+			}
+			centers[l.Index(x, y)] = [2]float32{float32(px) + float32(cell)/2, float32(py) + float32(cell)/2}
+		}
+	}
+
+	wire := color.RGBA{R: 0xFF, G: 0xA0, B: 0x20, A: 0x90}
+	for i := 1; i < len(centers); i++ {
+		a, b := centers[i-1], centers[i]
+		vector.StrokeLine(screen, a[0], a[1], b[0], b[1], 1, wire, false)
+	}
+	vector.FillCircle(screen, centers[0][0], centers[0][1], 3, color.RGBA{R: 0x34, G: 0xC7, B: 0x34, A: 0xff}, false)
+
+	// szacowany pobór prądu ramki
+	ma := g.power.StreamMA(g.buildWS2812Stream())
+	info := fmt.Sprintf("Pobór: %.0f / %d mA", ma, g.power.BudgetMA)
+	if scale := g.power.LimitScale(ma, l.NumLEDs()); scale < 1 {
+		if g.power.Limit {
+			info += fmt.Sprintf(" (limit %.0f%%)", scale*100)
+		} else {
+			info += " (!)"
+		}
+	}
+	drawText(screen, info, x0+8, y0+h-10)
 }
 
-func (g *Game) isInTextPreview(x, y int) bool {
-	return x >= GridW*CellSize+180 &&
-		y >= 12 &&
-		y <= 12+g.textViewH
+// fillRect rysuje wypełniony prostokąt (bez tworzenia obrazu na każde
+// wywołanie - rysowane są tysiące prostokątów na klatkę)
+func fillRect(img *ebiten.Image, x, y, w, h int, col color.Color) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	vector.FillRect(img, float32(x), float32(y), float32(w), float32(h), col, false)
 }
 
-func (g *Game) isInGlyphPreview(x, y int) bool {
-	return x >= GridW*CellSize+180 &&
-		y >= 12+g.textViewH+12 &&
-		y <= 12+g.textViewH+12+g.glyphViewH
+func drawText(screen *ebiten.Image, s string, x, y int) {
+	text.Draw(screen, s, fontFace, x, y, color.White)
 }
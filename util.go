@@ -23,6 +23,58 @@ func rgb565(c color.RGBA) uint16 {
 	return (r << 11) | (g << 5) | b
 }
 
+// rgb565ToRGBA - odwrotność rgb565 (5/6 bitów rozciągnięte na pełny bajt)
+func rgb565ToRGBA(v uint16) color.RGBA {
+	r := uint8(v >> 11 & 0x1F)
+	g := uint8(v >> 5 & 0x3F)
+	b := uint8(v & 0x1F)
+	return color.RGBA{R: r<<3 | r>>2, G: g<<2 | g>>4, B: b<<3 | b>>2, A: 0xff}
+}
+
+// rgb888 - 0x00RRGGBB (uint32_t / 3 bajty)
+func rgb888(c color.RGBA) uint32 {
+	return uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B)
+}
+
+// rgb332 - RRRGGGBB, 1 bajt na piksel
+func rgb332(c color.RGBA) uint8 {
+	return (c.R & 0xE0) | (c.G&0xE0)>>3 | c.B>>6
+}
+
+// rgb444 - 0x0RGB, 12 bitów w uint16_t
+func rgb444(c color.RGBA) uint16 {
+	return uint16(c.R>>4)<<8 | uint16(c.G>>4)<<4 | uint16(c.B>>4)
+}
+
+// bgr565 - BGR565 z zamienionymi bajtami, gotowe do wysyłki DMA
+// na ST7735 / ILI9341 (MADCTL BGR) z procesora little-endian
+func bgr565(c color.RGBA) uint16 {
+	v := uint16(c.B>>3)<<11 | uint16(c.G>>2)<<5 | uint16(c.R>>3)
+	return v<<8 | v>>8
+}
+
+// clampInt ogranicza v do zakresu lo..hi
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// clampFloat ogranicza v do zakresu lo..hi
+func clampFloat(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
 func chooseFilename(prefix, ext string) (string, error) {
 	if err := os.MkdirAll("export", 0755); err != nil {
 		return "", err
@@ -41,3 +93,12 @@ func chooseFilename(prefix, ext string) (string, error) {
 	}
 	return path, nil
 }
+
+// chooseOpenFilename - okno wyboru pliku do wczytania
+func chooseOpenFilename(title, desc string, exts ...string) (string, error) {
+	return dialog.File().
+		Title(title).
+		Filter(desc, exts...).
+		SetStartDir("export").
+		Load()
+}
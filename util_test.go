@@ -0,0 +1,48 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestColorPacking - formaty koloru pikseli w eksporcie
+func TestColorPacking(t *testing.T) {
+	tests := []struct {
+		c      color.RGBA
+		rgb565 uint16
+		rgb332 uint8
+		rgb444 uint16
+		bgr565 uint16
+	}{
+		{color.RGBA{0, 0, 0, 0xff}, 0x0000, 0x00, 0x000, 0x0000},
+		{color.RGBA{0xff, 0xff, 0xff, 0xff}, 0xFFFF, 0xFF, 0xFFF, 0xFFFF},
+		{color.RGBA{0xff, 0, 0, 0xff}, 0xF800, 0xE0, 0xF00, 0x1F00},
+		{color.RGBA{0, 0xff, 0, 0xff}, 0x07E0, 0x1C, 0x0F0, 0xE007},
+		{color.RGBA{0, 0, 0xff, 0xff}, 0x001F, 0x03, 0x00F, 0x00F8},
+		{color.RGBA{0x12, 0x34, 0x56, 0xff}, 0x11AA, 0x05, 0x135, 0xA251},
+		{color.RGBA{0xff, 0x80, 0x40, 0xff}, 0xFC08, 0xF1, 0xF84, 0x1F44},
+	}
+	for _, tt := range tests {
+		if got := rgb565(tt.c); got != tt.rgb565 {
+			t.Errorf("rgb565(%v) = %04X, want %04X", tt.c, got, tt.rgb565)
+		}
+		if got := rgb332(tt.c); got != tt.rgb332 {
+			t.Errorf("rgb332(%v) = %02X, want %02X", tt.c, got, tt.rgb332)
+		}
+		if got := rgb444(tt.c); got != tt.rgb444 {
+			t.Errorf("rgb444(%v) = %03X, want %03X", tt.c, got, tt.rgb444)
+		}
+		if got := bgr565(tt.c); got != tt.bgr565 {
+			t.Errorf("bgr565(%v) = %04X, want %04X", tt.c, got, tt.bgr565)
+		}
+	}
+}
+
+// TestRGB565RoundTrip - kolor zapisany w RGB565 wraca bez zmian
+func TestRGB565RoundTrip(t *testing.T) {
+	for _, v := range []uint16{0x0000, 0xFFFF, 0xF800, 0x07E0, 0x001F, 0x11AA, 0xFC08} {
+		if got := rgb565(rgb565ToRGBA(v)); got != v {
+			t.Errorf("rgb565(rgb565ToRGBA(%04X)) = %04X", v, got)
+		}
+	}
+}
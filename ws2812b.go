@@ -1,18 +1,278 @@
+/*
+Autor: SunRiver / Lothar TeaM
+  WWW: https://forum.lothar-team.pl/
+  Git: https://github.com/SunDUINO/Sun8x8_Font_generator.git
+ Plik: ws2812b.go
+
+*/
+
 package main
 
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
 type WS2812Color struct {
 	G uint8
 	R uint8
 	B uint8
 }
 
+// kolejność bajtów w strumieniu WS2812
+const (
+	WS2812OrderGRB = iota // natywna kolejność WS2812B / bufor NeoPixel NEO_GRB
+	WS2812OrderRGB        // np. tablica CRGB w FastLED (kolejność ustawia kontroler)
+)
+
+// NewWS2812Color przelicza kolor palety na kolor diody
+// z jasnością w procentach (0..100)
+func NewWS2812Color(c color.RGBA, brightness int) WS2812Color {
+	scale := func(v uint8) uint8 {
+		return uint8(int(v) * brightness / 100)
+	}
+	return WS2812Color{G: scale(c.G), R: scale(c.R), B: scale(c.B)}
+}
+
+// Bytes zwraca 3 bajty koloru w zadanej kolejności
+func (c WS2812Color) Bytes(order int) [3]byte {
+	if order == WS2812OrderRGB {
+		return [3]byte{c.R, c.G, c.B}
+	}
+	return [3]byte{c.G, c.R, c.B}
+}
+
+// narożnik, w którym jest pierwsza dioda panelu
+const (
+	OriginTopLeft = iota
+	OriginTopRight
+	OriginBottomLeft
+	OriginBottomRight
+)
+
+// WS2812Layout opisuje okablowanie wyświetlacza z paneli (domyślnie 8x8);
+// rozmiar panelu nie zależy od rozmiaru znaku - znaki są układane na
+// wyświetlaczu osobno (buildWS2812Display, ws2812Stream)
+type WS2812Layout struct {
+	PanelW     int  // szerokość panelu w diodach (0 = MatrixSize)
+	PanelH     int  // wysokość panelu w diodach (0 = MatrixSize)
+	Origin     int  // narożnik pierwszej diody panelu (Origin...)
+	Vertical   bool // true = diody biegną kolumnami, false = wierszami
+	Serpentine bool // true = zig-zag, false = każdy wiersz/kolumna od tej samej strony
+
+	TilesX         int  // liczba paneli w poziomie
+	TilesY         int  // liczba paneli w pionie
+	TileSerpentine bool // łańcuch paneli: co drugi rząd paneli od prawej
+}
+
+// domyślny panel: 1 matryca 8x8, zig-zag od lewego górnego rogu
+var defaultWS2812Layout = WS2812Layout{
+	PanelW:     MatrixSize,
+	PanelH:     MatrixSize,
+	Origin:     OriginTopLeft,
+	Serpentine: true,
+	TilesX:     1,
+	TilesY:     1,
+}
+
+// dostępne układy paneli (TilesX x TilesY)
+var ws2812Tilings = [][2]int{{1, 1}, {2, 1}, {4, 1}, {1, 2}, {2, 2}, {1, 4}}
+
+// dostępne rozmiary paneli (PanelW x PanelH)
+var ws2812Panels = [][2]int{{8, 8}, {16, 16}, {32, 8}, {8, 32}}
+
+// Panel zwraca rozmiar jednego panelu w diodach
+func (l WS2812Layout) Panel() (w, h int) {
+	w, h = l.PanelW, l.PanelH
+	if w <= 0 || h <= 0 {
+		w, h = MatrixSize, MatrixSize
+	}
+	return w, h
+}
+
+// Size zwraca rozmiar całego wyświetlacza w diodach
+func (l WS2812Layout) Size() (w, h int) {
+	pw, ph := l.Panel()
+	return l.TilesX * pw, l.TilesY * ph
+}
+
+// NumLEDs - liczba diod w łańcuchu
+func (l WS2812Layout) NumLEDs() int {
+	w, h := l.Size()
+	return w * h
+}
+
+// ForGlyph zwraca ten sam układ z najmniejszą liczbą paneli, na której
+// mieści się jeden znak GridW x GridH (eksport znaków, pobór prądu)
+func (l WS2812Layout) ForGlyph() WS2812Layout {
+	pw, ph := l.Panel()
+	l.TilesX, l.TilesY = (GridW+pw-1)/pw, (GridH+ph-1)/ph
+	return l
+}
+
+// Index mapuje współrzędne (x,y) wyświetlacza na indeks diody w łańcuchu
+func (l WS2812Layout) Index(x, y int) int {
+	pw, ph := l.Panel()
+	tx, ty := x/pw, y/ph
+	lx, ly := x%pw, y%ph
+
+	// kolejny panel w łańcuchu
+	tile := ty*l.TilesX + tx
+	if l.TileSerpentine && ty%2 == 1 {
+		tile = ty*l.TilesX + (l.TilesX - 1 - tx)
+	}
+
+	// narożnik startowy
+	if l.Origin == OriginTopRight || l.Origin == OriginBottomRight {
+		lx = pw - 1 - lx
+	}
+	if l.Origin == OriginBottomLeft || l.Origin == OriginBottomRight {
+		ly = ph - 1 - ly
+	}
+
+	// major = numer wiersza/kolumny w łańcuchu, minor = pozycja w nim
+	major, minor, length := ly, lx, pw
+	if l.Vertical {
+		major, minor, length = lx, ly, ph
+	}
+	if l.Serpentine && major%2 == 1 {
+		minor = length - 1 - minor
+	}
+
+	return tile*pw*ph + major*length + minor
+}
+
 // WS2812Index mapuje współrzędne (x,y) na liniowy indeks 0..63
 // przy układzie zigzag (serpentine):
 // rząd parzysty: 0→7, rząd nieparzysty: 15←8 dla y=1 itd.
 func WS2812Index(x, y int) int {
-	width := 8
-	if y%2 == 0 {
-		return y*width + x
+	return defaultWS2812Layout.Index(x, y)
+}
+
+// ws2812Stream zwraca znak jako strumień NumLEDs*3 bajtów w fizycznej
+// kolejności diod; znak w lewym górnym rogu paneli (ForGlyph), reszta zgaszona
+func ws2812Stream(gl Glyph, opts ExportOptions) []byte {
+	layout := opts.WS2812Layout.ForGlyph()
+	out := make([]byte, layout.NumLEDs()*3)
+	for y := 0; y < GridH; y++ {
+		for x := 0; x < GridW; x++ {
+			c := cellColor(gl[y][x])
+			if opts.WS2812Calib != nil {
+				c = opts.WS2812Calib.Apply(c)
+			}
+			b := NewWS2812Color(c, opts.WS2812Brightness).Bytes(opts.WS2812Order)
+			copy(out[layout.Index(x, y)*3:], b[:])
+		}
+	}
+	return out
+}
+
+// Describe zwraca czytelny opis układu (komentarz w eksporcie)
+func (l WS2812Layout) Describe() string {
+	dir := "wiersze"
+	if l.Vertical {
+		dir = "kolumny"
+	}
+	wiring := "progresywnie"
+	if l.Serpentine {
+		wiring = "zig-zag"
+	}
+	pw, ph := l.Panel()
+	return fmt.Sprintf("start %s, %s %s, panele %dx%d po %dx%d diod", originName(l.Origin), dir, wiring, l.TilesX, l.TilesY, pw, ph)
+}
+
+func originName(o int) string {
+	switch o {
+	case OriginTopRight:
+		return "prawy górny"
+	case OriginBottomLeft:
+		return "lewy dolny"
+	case OriginBottomRight:
+		return "prawy dolny"
+	}
+	return "lewy górny"
+}
+
+// LEDCalibration - korekcja kolorów dla diod adresowalnych.
+// Paleta jest w sRGB, a jasność diody rośnie liniowo z wartością PWM,
+// więc bez korekcji gamma kolory na WS2812B wyglądają na sprane.
+type LEDCalibration struct {
+	Gamma  float64 // wykładnik krzywej, 1.0 = bez korekcji
+	WhiteR int     // balans bieli: kanał R w %
+	WhiteG int     // balans bieli: kanał G w %
+	WhiteB int     // balans bieli: kanał B w %
+	MaxBri int     // globalne ograniczenie jasności w %
+}
+
+var defaultLEDCalibration = LEDCalibration{
+	Gamma:  2.2,
+	WhiteR: 100,
+	WhiteG: 100,
+	WhiteB: 100,
+	MaxBri: 100,
+}
+
+// Apply przelicza kolor palety na wartości wysyłane do diody
+func (c LEDCalibration) Apply(col color.RGBA) color.RGBA {
+	ch := func(v uint8, white int) uint8 {
+		f := math.Pow(float64(v)/255, c.Gamma)
+		f *= float64(white) / 100 * float64(c.MaxBri) / 100
+		return uint8(math.Round(clampFloat(f, 0, 1) * 255))
+	}
+	return color.RGBA{R: ch(col.R, c.WhiteR), G: ch(col.G, c.WhiteG), B: ch(col.B, c.WhiteB), A: 0xff}
+}
+
+// simulateLED pokazuje na ekranie (sRGB) jak wygląda liniowe światło diody
+// wysterowanej wartością col - służy do podglądu "jak na diodach"
+func simulateLED(col color.RGBA) color.RGBA {
+	ch := func(v uint8) uint8 {
+		return uint8(math.Round(math.Pow(float64(v)/255, 1/2.2) * 255))
+	}
+	return color.RGBA{R: ch(col.R), G: ch(col.G), B: ch(col.B), A: 0xff}
+}
+
+// prąd spoczynkowy jednej diody WS2812B (sterownik), mA
+const WS2812IdleMA = 1.0
+
+// PowerModel - szacowanie poboru prądu łańcucha WS2812B
+type PowerModel struct {
+	ChannelMA int  // prąd jednego kanału przy wartości 255, mA
+	BudgetMA  int  // dostępny prąd zasilania, mA
+	Limit     bool // automatyczne ograniczanie jasności do budżetu
+}
+
+var defaultPowerModel = PowerModel{
+	ChannelMA: 20,
+	BudgetMA:  500, // port USB 2.0
+}
+
+// StreamMA szacuje prąd dla strumienia bajtów (3 bajty na diodę)
+func (p PowerModel) StreamMA(stream []byte) float64 {
+	sum := 0
+	for _, v := range stream {
+		sum += int(v)
+	}
+	return float64(sum)/255*float64(p.ChannelMA) + WS2812IdleMA*float64(len(stream)/3)
+}
+
+// LimitScale zwraca współczynnik jasności (0..1), przy którym strumień
+// o poborze ma mieści się w budżecie
+func (p PowerModel) LimitScale(ma float64, leds int) float64 {
+	idle := WS2812IdleMA * float64(leds)
+	if ma <= float64(p.BudgetMA) || ma <= idle {
+		return 1
+	}
+	return clampFloat((float64(p.BudgetMA)-idle)/(ma-idle), 0, 1)
+}
+
+// LimitStream skaluje strumień w miejscu tak, by zmieścił się w budżecie
+func (p PowerModel) LimitStream(stream []byte) {
+	scale := p.LimitScale(p.StreamMA(stream), len(stream)/3)
+	if scale >= 1 {
+		return
+	}
+	for i, v := range stream {
+		stream[i] = uint8(float64(v) * scale)
 	}
-	return y*width + (width - 1 - x)
 }
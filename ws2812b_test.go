@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+)
+
+// TestWS2812IndexBijection - każdy układ paneli numeruje diody 0..NumLEDs-1
+// bez dziur i powtórzeń, a pierwsza dioda leży w narożniku Origin
+func TestWS2812IndexBijection(t *testing.T) {
+	for _, panel := range ws2812Panels {
+		for _, tiles := range ws2812Tilings {
+			for origin := OriginTopLeft; origin <= OriginBottomRight; origin++ {
+				for _, vertical := range []bool{false, true} {
+					for _, serp := range []bool{false, true} {
+						for _, tileSerp := range []bool{false, true} {
+							l := WS2812Layout{
+								PanelW: panel[0], PanelH: panel[1],
+								Origin: origin, Vertical: vertical, Serpentine: serp,
+								TilesX: tiles[0], TilesY: tiles[1], TileSerpentine: tileSerp,
+							}
+							checkWS2812Layout(t, l)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func checkWS2812Layout(t *testing.T, l WS2812Layout) {
+	t.Helper()
+	w, h := l.Size()
+	seen := make([]bool, l.NumLEDs())
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := l.Index(x, y)
+			if i < 0 || i >= len(seen) || seen[i] {
+				t.Errorf("%+v: Index(%d,%d) = %d poza zakresem albo powtórzony", l, x, y, i)
+				return
+			}
+			seen[i] = true
+		}
+	}
+
+	pw, ph := l.Panel()
+	x, y := 0, 0
+	if l.Origin == OriginTopRight || l.Origin == OriginBottomRight {
+		x = pw - 1
+	}
+	if l.Origin == OriginBottomLeft || l.Origin == OriginBottomRight {
+		y = ph - 1
+	}
+	if i := l.Index(x, y); i != 0 {
+		t.Errorf("%+v: pierwsza dioda Index(%d,%d) = %d, want 0", l, x, y, i)
+	}
+}
+
+// TestWS2812Index - wybrane pozycje w typowych układach
+func TestWS2812Index(t *testing.T) {
+	tl := defaultWS2812Layout
+	vert := tl
+	vert.Vertical = true
+	prog := tl
+	prog.Serpentine = false
+	br := tl
+	br.Origin = OriginBottomRight
+	wide := tl
+	wide.TilesX = 2
+	chain := tl
+	chain.TilesX, chain.TilesY = 2, 2
+	chainSerp := chain
+	chainSerp.TileSerpentine = true
+	strip := tl
+	strip.PanelW, strip.PanelH = 32, 8
+
+	tests := []struct {
+		name string
+		l    WS2812Layout
+		x, y int
+		want int
+		leds int
+	}{
+		{"zig-zag", tl, 7, 0, 7, 64},
+		{"zig-zag", tl, 7, 1, 8, 64},
+		{"zig-zag", tl, 0, 1, 15, 64},
+		{"progresywnie", prog, 0, 1, 8, 64},
+		{"kolumny", vert, 0, 7, 7, 64},
+		{"kolumny", vert, 1, 7, 8, 64},
+		{"prawy dolny", br, 6, 7, 1, 64},
+		{"prawy dolny", br, 7, 6, 15, 64},
+		{"2x1", wide, 8, 0, 64, 128},
+		{"2x2", chain, 0, 8, 128, 256},
+		{"2x2 zig-zag paneli", chainSerp, 0, 8, 192, 256},
+		{"2x2 zig-zag paneli", chainSerp, 8, 8, 128, 256},
+		{"panel 32x8", strip, 31, 1, 32, 256},
+	}
+	for _, tt := range tests {
+		if got := tt.l.Index(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: Index(%d,%d) = %d, want %d", tt.name, tt.x, tt.y, got, tt.want)
+		}
+		if got := tt.l.NumLEDs(); got != tt.leds {
+			t.Errorf("%s: NumLEDs = %d, want %d", tt.name, got, tt.leds)
+		}
+	}
+
+	// zerowy rozmiar panelu = matryca 8x8 (projekty sprzed PanelW/PanelH)
+	old := WS2812Layout{Serpentine: true, TilesX: 1, TilesY: 1}
+	if w, h := old.Panel(); w != MatrixSize || h != MatrixSize {
+		t.Errorf("Panel() bez rozmiaru = %dx%d, want %dx%d", w, h, MatrixSize, MatrixSize)
+	}
+}
+
+// TestWS2812ForGlyph - najmniejsza liczba paneli mieszcząca znak
+func TestWS2812ForGlyph(t *testing.T) {
+	tests := []struct {
+		w, h, pw, ph int
+		tx, ty       int
+	}{
+		{8, 8, 8, 8, 1, 1},
+		{5, 7, 8, 8, 1, 1},
+		{8, 16, 8, 8, 1, 2},
+		{16, 16, 8, 8, 2, 2},
+		{16, 16, 16, 16, 1, 1},
+		{16, 8, 32, 8, 1, 1},
+		{16, 16, 8, 32, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%dx%d/panel%dx%d", tt.w, tt.h, tt.pw, tt.ph), func(t *testing.T) {
+			setGridSize(t, tt.w, tt.h)
+			l := defaultWS2812Layout
+			l.PanelW, l.PanelH = tt.pw, tt.ph
+			l.TilesX, l.TilesY = 4, 4
+			g := l.ForGlyph()
+			if g.TilesX != tt.tx || g.TilesY != tt.ty {
+				t.Errorf("panele %dx%d, want %dx%d", g.TilesX, g.TilesY, tt.tx, tt.ty)
+			}
+			opts := ExportOptions{WS2812Brightness: 100, WS2812Layout: l}
+			if n := len(ws2812Stream(Glyph{}, opts)); n != g.NumLEDs()*3 {
+				t.Errorf("strumień %d bajtów, want %d", n, g.NumLEDs()*3)
+			}
+		})
+	}
+}
+
+// TestLEDCalibrationApply - gamma, balans bieli i ograniczenie jasności
+func TestLEDCalibrationApply(t *testing.T) {
+	linear := LEDCalibration{Gamma: 1, WhiteR: 100, WhiteG: 100, WhiteB: 100, MaxBri: 100}
+	half := linear
+	half.MaxBri = 50
+	warm := linear
+	warm.WhiteB = 0
+
+	tests := []struct {
+		name string
+		cal  LEDCalibration
+		in   color.RGBA
+		want color.RGBA
+	}{
+		{"bez korekcji", linear, rgb(0x12, 0x80, 0xff), rgb(0x12, 0x80, 0xff)},
+		{"gamma 2.2", defaultLEDCalibration, rgb(0, 128, 255), rgb(0, 56, 255)},
+		{"jasność 50%", half, rgb(255, 100, 0), rgb(128, 50, 0)},
+		{"balans bieli", warm, rgb(255, 255, 255), rgb(255, 255, 0)},
+	}
+	for _, tt := range tests {
+		if got := tt.cal.Apply(tt.in); got != tt.want {
+			t.Errorf("%s: Apply(%v) = %v, want %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestPowerModelLimitStream - strumień ponad budżet jest skalowany do budżetu
+func TestPowerModelLimitStream(t *testing.T) {
+	p := defaultPowerModel
+
+	white := make([]byte, 64*3)
+	for i := range white {
+		white[i] = 0xff
+	}
+	if ma := p.StreamMA(white); ma != 64*3*20+64 {
+		t.Fatalf("StreamMA(64 x biały) = %v, want %v", ma, 64*3*20+64)
+	}
+	p.LimitStream(white)
+	// wartości obcinane w dół - do 1 na bajt poniżej budżetu
+	floor := float64(p.BudgetMA) - float64(len(white)*p.ChannelMA)/255
+	if ma := p.StreamMA(white); ma > float64(p.BudgetMA) || ma < floor {
+		t.Errorf("po LimitStream %v mA, want ~%d", ma, p.BudgetMA)
+	}
+	for _, v := range white[1:] {
+		if v != white[0] {
+			t.Fatalf("LimitStream zmienił proporcje: % X", white)
+		}
+	}
+
+	// w budżecie - bez zmian
+	small := []byte{0xff, 0x80, 0x00}
+	p.LimitStream(small)
+	if small[0] != 0xff || small[1] != 0x80 || small[2] != 0 {
+		t.Errorf("strumień w budżecie zmieniony: % X", small)
+	}
+
+	// budżet poniżej prądu spoczynkowego - nie da się nic zrobić
+	p.BudgetMA = 10
+	if s := p.LimitScale(p.StreamMA(make([]byte, 64*3)), 64); s != 1 {
+		t.Errorf("LimitScale dla zgaszonych diod = %v, want 1", s)
+	}
+}